@@ -0,0 +1,177 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"pqr/history"
+	"pqr/launch"
+	"pqr/ui"
+)
+
+// maxConsoleRows는 콘솔이 보관하는 최대 줄 수입니다. 넘으면 앞부분부터 버립니다.
+const maxConsoleRows = 10000
+
+// outputConsole은 터미널 없이 실행한 스크립트 하나의 출력을 실시간으로
+// 보여 주는 창입니다. stderr는 오류 색으로, ANSI 색상은 그대로 표시합니다.
+type outputConsole struct {
+	w       fyne.Window
+	out     *ui.Output
+	status  *widget.Label
+	stopBtn *widget.Button
+	input   *widget.Entry  // stdin으로 보낼 줄
+	eofBtn  *widget.Button // stdin 닫기
+	run     *launch.Run    // 시작된 뒤에 설정
+}
+
+// newOutputConsole은 s의 출력 창을 엽니다. 위쪽에 명령줄과 인터프리터(결정
+// 규칙 포함)를 보여 주며, rerun은 "Re-run" 버튼이 호출합니다.
+func (l *LauncherApp) newOutputConsole(s ScriptItem, argv []string, interpreter string, rerun func()) *outputConsole {
+	c := &outputConsole{
+		w:      l.App.NewWindow(s.Name + " - Output"),
+		out:    ui.NewOutput(maxConsoleRows),
+		status: widget.NewLabel("Running..."),
+	}
+
+	command := widget.NewLabel(strings.Join(argv, " "))
+	command.Truncation = fyne.TextTruncateEllipsis
+	interp := widget.NewLabel("Interpreter: " + interpreter)
+	interp.Truncation = fyne.TextTruncateEllipsis
+
+	copyBtn := widget.NewButtonWithIcon("Copy", theme.ContentCopyIcon(), func() {
+		l.App.Clipboard().SetContent(c.out.Grid.Text())
+	})
+	saveBtn := widget.NewButtonWithIcon("Save", theme.DocumentSaveIcon(), func() {
+		d := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil || writer == nil {
+				return
+			}
+			defer writer.Close()
+			if _, err := writer.Write([]byte(c.out.Grid.Text())); err != nil {
+				dialog.ShowError(err, c.w)
+			}
+		}, c.w)
+		d.SetFileName(s.Name + ".log")
+		d.Show()
+	})
+	rerunBtn := widget.NewButtonWithIcon("Re-run", theme.MediaReplayIcon(), rerun)
+	c.stopBtn = widget.NewButtonWithIcon("Stop", theme.MediaStopIcon(), func() {
+		if c.run != nil {
+			if err := c.run.Stop(); err != nil {
+				dialog.ShowError(err, c.w)
+			}
+		}
+	})
+	c.stopBtn.Disable()
+
+	// 입력: 실행이 stdin 파이프를 가질 때만 connectInput이 켭니다
+	c.input = widget.NewEntry()
+	c.input.SetPlaceHolder("Input for the script (Enter sends a line)")
+	c.input.Disable()
+	c.eofBtn = widget.NewButton("EOF", nil)
+	c.eofBtn.Disable()
+
+	buttons := container.NewHBox(c.stopBtn, copyBtn, saveBtn, rerunBtn)
+	bottom := container.NewVBox(
+		container.NewBorder(nil, nil, nil, c.eofBtn, c.input),
+		container.NewBorder(nil, nil, nil, buttons, c.status),
+	)
+	c.w.SetContent(container.NewBorder(container.NewVBox(command, interp), bottom, nil, nil, c.out.Grid))
+	c.w.Resize(fyne.NewSize(720, 460))
+	c.w.Show()
+	return c
+}
+
+// write는 stream의 출력 조각을 표시합니다. UI 스레드에서 호출해야 합니다.
+func (c *outputConsole) write(stream launch.Stream, data string) {
+	c.out.Write(stream, data)
+}
+
+// connectInput은 입력 칸을 stdin에 연결합니다. 파이프는 입력을 되돌려 주지
+// 않으므로 보낸 줄을 콘솔에 직접 표시하고 echo에도 넘깁니다. EOF 버튼은
+// stdin을 닫습니다. UI 스레드에서 호출해야 합니다.
+func (c *outputConsole) connectInput(stdin *launch.StdinWriter, echo func(string)) {
+	c.input.OnSubmitted = func(line string) {
+		c.input.SetText("")
+		text := line + "\n"
+		c.write(launch.Stdout, text)
+		echo(text)
+		stdin.Write(text)
+	}
+	c.eofBtn.OnTapped = func() {
+		stdin.Close()
+		c.input.Disable()
+		c.eofBtn.Disable()
+	}
+	c.input.Enable()
+	c.eofBtn.Enable()
+	c.w.Canvas().Focus(c.input)
+}
+
+// finish는 종료 코드와 실행 시간을 표시합니다. 제한 시간에 걸려 멈춘 실행은
+// 실패와 구분해 표시합니다. UI 스레드에서 호출해야 합니다.
+func (c *outputConsole) finish(code int, d time.Duration, timedOut bool) {
+	msg := fmt.Sprintf("Exited with code %d after %s", code, d.Round(time.Millisecond))
+	switch {
+	case timedOut:
+		msg = fmt.Sprintf("Timed out after %s", d.Round(time.Millisecond))
+	case code == -1:
+		msg = fmt.Sprintf("Killed after %s", d.Round(time.Millisecond))
+	}
+	c.out.Note(msg)
+
+	switch {
+	case timedOut:
+		c.status.Importance = widget.WarningImportance
+	case code != 0:
+		c.status.Importance = widget.DangerImportance
+	}
+	c.status.SetText(msg)
+	c.stopBtn.Disable()
+	c.input.Disable()
+	c.eofBtn.Disable()
+}
+
+// startWithConsole은 spec을 터미널 없이 실행하고 출력을 새 콘솔 창에 보여 줍니다.
+// 출력은 실행 기록용으로도 모아 둡니다.
+func (l *LauncherApp) startWithConsole(s ScriptItem, spec launch.Spec, rec history.Record) bool {
+	c := l.newOutputConsole(s, spec.Argv, rec.Interpreter, func() { l.runScript(s) })
+	capture := &history.Capture{}
+	run, err := spec.Start(func(stream launch.Stream, data []byte) {
+		capture.Write(stream == launch.Stderr, data)
+		text := string(data)
+		fyne.Do(func() { c.write(stream, text) })
+	})
+	if err != nil {
+		c.status.Importance = widget.DangerImportance
+		c.status.SetText("Failed to start: " + err.Error())
+		return false
+	}
+	c.run = run
+	c.stopBtn.Enable()
+	// 스크립트가 입력을 읽지 않아도 창이 멈추지 않도록 별도 고루틴에서 씁니다
+	stdin := launch.NewStdinWriter(run, func(err error) {
+		fyne.Do(func() { c.status.SetText("Input not sent: " + err.Error()) })
+	})
+	if stdin != nil {
+		c.connectInput(stdin, func(text string) { capture.Write(false, []byte(text)) })
+	} else if spec.Stdin != "" {
+		c.input.SetPlaceHolder("stdin: " + spec.Stdin)
+	}
+	l.trackRun(s, run, "", c)
+	go func() {
+		code, d := run.ExitCode(), run.Duration()
+		fyne.Do(func() { c.finish(code, d, run.TimedOut()) })
+		l.recordRun(rec, run, capture)
+	}()
+	return true
+}
@@ -0,0 +1,165 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"pqr/history"
+	"pqr/launch"
+)
+
+// recordRun은 끝난 run의 결과를 rec에 채워 실행 기록에 저장합니다.
+// capture가 nil이면 (터미널 실행) 출력 없이 저장합니다.
+func (l *LauncherApp) recordRun(rec history.Record, run *launch.Run, capture *history.Capture) {
+	rec.Started = run.Started
+	rec.Ended = run.Started.Add(run.Duration())
+	rec.ExitCode = run.ExitCode()
+	rec.TimedOut = run.TimedOut()
+	var output []history.Chunk
+	if capture != nil {
+		output, rec.Truncated = capture.Chunks()
+	}
+	if err := l.History.Add(&rec, output); err != nil {
+		fmt.Printf("Error saving run history: %v\n", err)
+		return
+	}
+	fyne.Do(l.refreshHistory)
+}
+
+// refreshHistory는 기록 창이 열려 있으면 목록을 다시 읽습니다.
+func (l *LauncherApp) refreshHistory() {
+	if l.HistoryList == nil {
+		return
+	}
+	records, err := l.History.List(0)
+	if err != nil {
+		fmt.Printf("Error reading run history: %v\n", err)
+	}
+	l.HistoryItems = records
+	l.HistoryList.Refresh()
+}
+
+// showHistoryDialog는 실행 기록 창을 엽니다.
+func (l *LauncherApp) showHistoryDialog() {
+	if l.HistoryWindow != nil {
+		l.HistoryWindow.Show()
+		l.HistoryWindow.RequestFocus()
+		return
+	}
+
+	w := l.App.NewWindow("Run History")
+	l.HistoryWindow = w
+
+	l.HistoryList = widget.NewList(
+		func() int { return len(l.HistoryItems) },
+		func() fyne.CanvasObject {
+			title := widget.NewLabelWithStyle("template", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+			detail := widget.NewLabel("detail")
+			detail.Truncation = fyne.TextTruncateEllipsis
+			outputBtn := widget.NewButtonWithIcon("Output", theme.DocumentIcon(), nil)
+			rerunBtn := widget.NewButtonWithIcon("Re-run", theme.MediaReplayIcon(), nil)
+			return container.NewBorder(nil, nil, nil,
+				container.NewHBox(outputBtn, rerunBtn),
+				container.NewVBox(title, detail),
+			)
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i >= len(l.HistoryItems) {
+				return
+			}
+			rec := l.HistoryItems[i]
+			c := o.(*fyne.Container)
+			labels := c.Objects[0].(*fyne.Container)
+			buttons := c.Objects[1].(*fyne.Container)
+
+			title := labels.Objects[0].(*widget.Label)
+			title.SetText(fmt.Sprintf("%s  ·  %s  ·  %s  ·  %s",
+				strings.TrimSuffix(filepath.Base(rec.Script), ".py"),
+				rec.Started.Format("2006-01-02 15:04:05"),
+				exitText(rec),
+				rec.Duration().Round(time.Millisecond)))
+			title.Importance = widget.MediumImportance
+			switch {
+			case rec.TimedOut:
+				title.Importance = widget.WarningImportance
+			case rec.ExitCode != 0 && !rec.Terminal:
+				title.Importance = widget.DangerImportance
+			}
+			title.Refresh()
+			labels.Objects[1].(*widget.Label).SetText(rec.Interpreter + "  ·  " + rec.Dir)
+
+			outputBtn := buttons.Objects[0].(*widget.Button)
+			outputBtn.OnTapped = func() { l.showRunOutput(rec) }
+			if rec.Terminal {
+				outputBtn.Disable()
+			} else {
+				outputBtn.Enable()
+			}
+			buttons.Objects[1].(*widget.Button).OnTapped = func() { l.rerun(rec) }
+		},
+	)
+
+	w.SetContent(l.HistoryList)
+	w.Resize(fyne.NewSize(720, 420))
+	w.SetOnClosed(func() {
+		l.HistoryWindow = nil
+		l.HistoryList = nil
+		l.HistoryItems = nil
+	})
+	l.refreshHistory()
+	w.Show()
+}
+
+// exitText는 기록의 종료 상태를 짧게 표시합니다.
+func exitText(rec *history.Record) string {
+	switch {
+	case rec.Terminal:
+		return "terminal"
+	case rec.TimedOut:
+		return "timed out"
+	case rec.ExitCode == -1:
+		return "killed"
+	}
+	return fmt.Sprintf("exit %d", rec.ExitCode)
+}
+
+// showRunOutput은 기록된 출력을 콘솔 창에 다시 보여 줍니다.
+func (l *LauncherApp) showRunOutput(rec *history.Record) {
+	output, err := l.History.Output(rec.ID)
+	if err != nil {
+		dialog.ShowError(err, l.HistoryWindow)
+		return
+	}
+	c := l.newOutputConsole(newScriptItem(rec.Script), rec.Argv, rec.Interpreter, func() { l.rerun(rec) })
+	if rec.Truncated {
+		c.write(launch.Stderr, fmt.Sprintf("[output truncated to the last %d KiB]\n", history.MaxOutput/1024))
+	}
+	for _, chunk := range output {
+		stream := launch.Stdout
+		if chunk.Stderr {
+			stream = launch.Stderr
+		}
+		c.write(stream, chunk.Text)
+	}
+	c.finish(rec.ExitCode, rec.Duration(), rec.TimedOut)
+}
+
+// rerun은 기록된 스크립트를 현재 헤더와 설정으로 다시 실행합니다.
+func (l *LauncherApp) rerun(rec *history.Record) {
+	if _, err := os.Stat(rec.Script); err != nil {
+		dialog.ShowError(err, l.Window)
+		return
+	}
+	l.runScript(newScriptItem(rec.Script))
+}
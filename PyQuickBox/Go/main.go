@@ -3,17 +3,18 @@
 package main
 
 import (
-	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"image/color"
 	"io/ioutil"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -26,19 +27,21 @@ import (
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+
+	"pqr"
+	"pqr/history"
+	"pqr/launch"
+	"pqr/ui"
 )
 
 // --- 데이터 모델 ---
 type ScriptItem struct {
-	Name          string
-	Path          string
-	Category      string
-	IconPath      string
-	InterpDefault string // #pqr ... (Legacy or fallback)
-	InterpMac     string // #pqr mac
-	InterpWin     string // #pqr win
-	InterpUbuntu  string // #pqr ubuntu
-	Terminal      bool   // #pqr terminal true
+	Name      string
+	Path      string
+	Category  string
+	IconPath  string
+	Header    pqr.Header // #pqr 헤더
+	HeaderErr error      // #pqr 문법 오류 (pqr.ErrorList) 또는 읽기 오류
 }
 
 // --- 앱 설정 키 ---
@@ -49,6 +52,12 @@ const (
 	KeyFontSize          = "FontSize"
 	KeyUIScale           = "UIScale"
 	KeyThemeMode         = "ThemeMode" // "dark", "light", "system"
+	KeyBackupOnSave      = "BackupOnSave"
+	KeyMetadataRunner    = "MetadataRunner"
+	KeyDefaultRunner     = "DefaultRunner"
+	KeyInterpreters      = "Interpreters"      // py= 이름 → 인터프리터 경로 (JSON)
+	KeyDefaultTimeout    = "DefaultTimeout"    // timeout= 이 없을 때의 실행 제한 시간 ("" = 제한 없음)
+	KeyMaxRuns           = "MaxConcurrentRuns" // 동시에 실행할 스크립트 수 (0 = 제한 없음)
 )
 
 const (
@@ -71,12 +80,31 @@ type LauncherApp struct {
 	// Settings Window
 	SettingsWindow fyne.Window
 
+	// 실행 중인 스크립트 (processes.go)
+	Running       []*runningScript
+	Queued        []*queuedScript
+	ProcessWindow fyne.Window
+	ProcessList   *widget.List
+	ProcessesBtn  *widget.Button
+
 	// 설정
 	DefaultPythonPath string
 	IconSize          float32
 	FontSize          float32
 	UIScale           float32
-	ThemeMode         string // "dark", "light", "system"
+	ThemeMode         string            // "dark", "light", "system"
+	BackupOnSave      bool              // 헤더 저장 시 .bak 사본 생성
+	MetadataRunner    string            // PEP 723 스크립트 실행기 ("" = 인터프리터로 직접 실행)
+	DefaultRunner     string            // runner= 가 없을 때의 실행기 (python, uv, poetry, ...)
+	Interpreters      map[string]string // 이름 있는 인터프리터 (py=ds311)
+	DefaultTimeout    string            // timeout= 이 없을 때의 제한 시간 (90s, 10m, "" = 없음)
+	MaxRuns           int               // 동시 실행 수 제한, 넘으면 대기열에 넣음 (0 = 제한 없음)
+
+	// 실행 기록 (historyview.go)
+	History       *history.Store
+	HistoryWindow fyne.Window
+	HistoryList   *widget.List
+	HistoryItems  []*history.Record
 
 	// 검색
 	SearchText  string
@@ -114,6 +142,7 @@ func main() {
 	launcher.applyTheme(launcher.ThemeMode)
 
 	// 2) UI 구성
+	launcher.History = history.Open(filepath.Join(myApp.Storage().RootURI().Path(), "history"))
 	launcher.setupUI()
 
 	// 3) 초기 스캔 (한 번만)
@@ -178,101 +207,26 @@ func (l *LauncherApp) handleDrops(uris []fyne.URI) {
 		} else {
 			// 파일 드롭: .py 확인
 			if filepath.Ext(path) == ".py" {
-				cat, iMac, iWin, iUbu, term, iDef := l.parseHeader(path)
-				item := ScriptItem{
-					Name:          strings.TrimSuffix(filepath.Base(path), ".py"),
-					Path:          path,
-					Category:      cat,
-					InterpMac:     iMac,
-					InterpWin:     iWin,
-					InterpUbuntu:  iUbu,
-					Terminal:      term,
-					InterpDefault: iDef,
-				}
-				l.runScript(item)
+				l.runScript(newScriptItem(path))
 			}
 		}
 	}
 }
 
-// parseHeader는 스크립트 파일의 #pqr 주석을 파싱하여 메타데이터를 추출합니다.
-func (l *LauncherApp) parseHeader(filePath string) (category, interpMac, interpWin, interpUbuntu string, terminal bool, interpDefault string) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return "Uncategorized", "", "", "", false, ""
+// newScriptItem은 스크립트 파일의 #pqr 헤더를 읽어 ScriptItem을 만듭니다.
+func newScriptItem(path string) ScriptItem {
+	header, err := pqr.ParseFile(path) // 오류는 속성 창에 표시
+	category := header.Category
+	if category == "" {
+		category = "Uncategorized" // Default
 	}
-	defer file.Close()
-
-	category = "Uncategorized" // Default
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if !strings.HasPrefix(line, "#pqr") {
-			continue
-		}
-
-		// 1) New Format: #pqr key=val; key=val
-		if strings.Contains(line, "=") {
-			content := strings.TrimSpace(strings.TrimPrefix(line, "#pqr"))
-			parts := strings.Split(content, ";")
-			for _, part := range parts {
-				kv := strings.SplitN(part, "=", 2)
-				if len(kv) != 2 {
-					continue
-				}
-				key := strings.TrimSpace(kv[0])
-				val := strings.TrimSpace(kv[1])
-				switch strings.ToLower(key) {
-				case "cat":
-					category = val
-				case "mac":
-					interpMac = val
-				case "win":
-					interpWin = val
-				case "linux", "ubuntu":
-					interpUbuntu = val
-				case "term":
-					terminal = (strings.ToLower(val) == "true")
-				case "def":
-					interpDefault = val
-				}
-			}
-			continue
-		}
-
-		// 2) Legacy Format: #pqr key "val"
-		if strings.HasPrefix(line, "#pqr cat") {
-			re := regexp.MustCompile(`#pqr\s+cat\s+"([^"]+)"`)
-			matches := re.FindStringSubmatch(line)
-			if len(matches) > 1 {
-				category = matches[1]
-			}
-		} else if strings.HasPrefix(line, "#pqr mac") {
-			re := regexp.MustCompile(`#pqr\s+mac\s+"([^"]+)"`)
-			matches := re.FindStringSubmatch(line)
-			if len(matches) > 1 {
-				interpMac = matches[1]
-			}
-		} else if strings.HasPrefix(line, "#pqr win") {
-			re := regexp.MustCompile(`#pqr\s+win\s+"([^"]+)"`)
-			matches := re.FindStringSubmatch(line)
-			if len(matches) > 1 {
-				interpWin = matches[1]
-			}
-		} else if strings.HasPrefix(line, "#pqr ubuntu") {
-			re := regexp.MustCompile(`#pqr\s+ubuntu\s+"([^"]+)"`)
-			matches := re.FindStringSubmatch(line)
-			if len(matches) > 1 {
-				interpUbuntu = matches[1]
-			}
-		} else if strings.HasPrefix(line, "#pqr terminal") {
-			if strings.Contains(line, "true") {
-				terminal = true
-			}
-		}
+	return ScriptItem{
+		Name:      strings.TrimSuffix(filepath.Base(path), ".py"),
+		Path:      path,
+		Category:  category,
+		Header:    header,
+		HeaderErr: err,
 	}
-	return
 }
 
 // 테마 적용
@@ -424,9 +378,20 @@ func (l *LauncherApp) setupUI() {
 
 	themeBtn := NewThemeButton(l)
 
+	// 실행 중인 스크립트 (개수 표시)
+	l.ProcessesBtn = widget.NewButtonWithIcon("", theme.MediaPlayIcon(), func() {
+		l.showProcessesDialog()
+	})
+
+	historyBtn := widget.NewButtonWithIcon("", theme.HistoryIcon(), func() {
+		l.showHistoryDialog()
+	})
+
 	topRightControls := container.NewHBox(
 		sliderContainer,
 		themeBtn,
+		l.ProcessesBtn,
+		historyBtn,
 		refreshBtn,
 		settingsBtn,
 	)
@@ -543,22 +508,11 @@ func (l *LauncherApp) refreshScripts() {
 				iconPath = defaultIcon
 			}
 
-			cat, iMac, iWin, iUbu, term, iDef := l.parseHeader(fullPath)
-
-			item := ScriptItem{
-				Name:          fileName,
-				Path:          fullPath,
-				Category:      cat,
-				IconPath:      iconPath,
-				InterpMac:     iMac,
-				InterpWin:     iWin,
-				InterpUbuntu:  iUbu,
-				Terminal:      term,
-				InterpDefault: iDef,
-			}
+			item := newScriptItem(fullPath)
+			item.IconPath = iconPath
 
-			newScripts[cat] = append(newScripts[cat], item)
-			newCategories[cat] = true
+			newScripts[item.Category] = append(newScripts[item.Category], item)
+			newCategories[item.Category] = true
 		}
 	}
 
@@ -589,91 +543,135 @@ func (l *LauncherApp) refreshScripts() {
 }
 
 // --- 로직: 실행 ---
-func (l *LauncherApp) runScript(s ScriptItem) *exec.Cmd {
-	var python string
-
-	switch runtime.GOOS {
-	case "darwin":
-		if s.InterpMac != "" {
-			python = s.InterpMac
-		}
-	case "windows":
-		if s.InterpWin != "" {
-			python = s.InterpWin
-		}
-	case "linux":
-		if s.InterpUbuntu != "" {
-			python = s.InterpUbuntu
-		}
+func (l *LauncherApp) runScript(s ScriptItem) {
+	// 실행기: runner= > #pqr 인터프리터 > PEP 723 실행기 > 기본 실행기
+	runner, err := launch.SelectRunner(s.Header, runtime.GOOS, l.DefaultRunner, l.MetadataRunner)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s: %v", s.Name, err), l.Window)
+		return
 	}
 
-	if python == "" {
-		python = s.InterpDefault
+	// 인터프리터: #pqr (경로, py=, conda=) > venv > .python-version > environment.yml > 기본 경로
+	// (py>=3.x / requires-python 이 있으면 조건에 맞는 인터프리터)
+	// 후보 확인에 몇 초가 걸릴 수 있어 UI 스레드 밖에서 결정합니다.
+	resolver := l.resolver()
+	resolver.SkipConstraint = !runner.UsesPython()
+	go func() {
+		interp, err := resolver.Resolve(s.Header, runtime.GOOS, s.Path)
+		fyne.Do(func() { l.runResolved(s, runner, interp, err) })
+	}()
+}
+
+// runResolved는 결정된 인터프리터로 s의 실행을 준비해 schedule에 넘깁니다.
+// err는 인터프리터 결정 오류입니다.
+func (l *LauncherApp) runResolved(s ScriptItem, runner launch.Runner, interp launch.Interpreter, err error) {
+	var aliasErr *launch.UnknownAliasError
+	if errors.As(err, &aliasErr) {
+		ui.OfferInterpreterMapping(l.Window, aliasErr.Name, func(python string) {
+			l.Interpreters[aliasErr.Name] = python
+			l.savePreferences()
+			l.runScript(s)
+		})
+		return
+	}
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s: %v", s.Name, err), l.Window)
+		return
 	}
-	if python == "" {
-		python = l.DefaultPythonPath
+
+	flags, err := s.Header.InterpreterFlags()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s: pyflags: %v", s.Name, err), l.Window)
+		return
 	}
-	if python == "" {
-		if runtime.GOOS == "windows" {
-			python = "python"
-		} else {
-			python = "/usr/bin/python3"
-		}
+	args, err := s.Header.ScriptArgs()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s: args: %v", s.Name, err), l.Window)
+		return
 	}
 
-	fmt.Printf("Run Code: %s / Python: %s\n", s.Name, python)
+	env, err := s.Header.Environ(filepath.Dir(s.Path))
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s: %v", s.Name, err), l.Window)
+		return
+	}
+	workDir, err := s.Header.WorkDir(s.Path)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s: %v", s.Name, err), l.Window)
+		return
+	}
+	// stdin=file:<path> 이 있으면 그 파일을, 없으면 콘솔 입력 칸을 stdin으로 (터미널 실행 제외)
+	stdinFile, err := s.Header.StdinFile(s.Path)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s: %v", s.Name, err), l.Window)
+		return
+	}
+	// 제한 시간: timeout= > 기본 설정 (터미널 실행에는 적용되지 않음)
+	timeout, err := launch.Timeout(s.Header, l.DefaultTimeout)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s: %v", s.Name, err), l.Window)
+		return
+	}
 
-	var cmd *exec.Cmd
-	if s.Terminal {
-		cmd = l.createTerminalCommand(python, s.Path)
-	} else {
-		cmd = exec.Command(python, s.Path)
+	argv, err := runner.Argv(interp.Python, flags, s.Path, args)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s: %v", s.Name, err), l.Window)
+		return
+	}
+	if !runner.UsesPython() {
+		interp.Venv = "" // 실행기가 환경을 직접 고름
 	}
 
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Env = append(os.Environ(), "PYTHONUNBUFFERED=1")
+	spec := launch.Spec{
+		Argv: argv,
+		Dir:  workDir,
+		Env:  append(append([]string{"PYTHONUNBUFFERED=1"}, interp.Environ()...), env...),
 
-	go func() {
-		if err := cmd.Run(); err != nil {
-			fmt.Printf("Error running script: %v\n", err)
-			dialog.ShowError(err, l.Window)
-		}
-	}()
-	return cmd
+		Timeout: timeout,
+		Stdin:   stdinFile,
+	}
+	rec := history.Record{
+		Script:      s.Path,
+		Interpreter: fmt.Sprintf("%s (%s)", interp.Python, interp.Source),
+		Argv:        argv,
+		Args:        args,
+		Dir:         workDir,
+		Terminal:    s.Header.Terminal(),
+	}
+	if !runner.UsesPython() {
+		rec.Interpreter = runner.Name
+	}
+	l.schedule(s, func() bool { return l.startScript(s, spec, rec) })
 }
 
-func (l *LauncherApp) createTerminalCommand(python, scriptPath string) *exec.Cmd {
-	switch runtime.GOOS {
-	case "darwin":
-		script := fmt.Sprintf(`tell application "Terminal" to do script "%s %s"`, python, scriptPath)
-		return exec.Command("osascript", "-e", script)
-	case "windows":
-		return exec.Command("cmd", "/C", "start", "cmd", "/k", python, scriptPath)
-	case "linux":
-		cmdStr := fmt.Sprintf("%s %s; echo; echo 'Exit Code: $?'; read -p 'Press Enter to exit...'",
-			shellQuote(python), shellQuote(scriptPath))
-
-		terminals := [][]string{
-			{"ptyxis", "--", "bash", "-c"},
-			{"kgx", "--", "bash", "-c"},
-			{"gnome-terminal", "--", "bash", "-c"},
-			{"konsole", "-e", "bash", "-c"},
-			{"xfce4-terminal", "-x", "bash", "-c"},
-			{"xterm", "-e", "bash", "-c"},
-		}
+// resolver는 현재 설정으로 인터프리터 결정기를 만듭니다. 다른 고루틴에서
+// 쓸 수 있도록 이름 목록은 복사합니다.
+func (l *LauncherApp) resolver() launch.Resolver {
+	return launch.Resolver{Default: l.DefaultPythonPath, Aliases: maps.Clone(l.Interpreters)}
+}
 
-		for _, term := range terminals {
-			if _, err := exec.LookPath(term[0]); err == nil {
-				args := append(term[1:], cmdStr)
-				return exec.Command(term[0], args...)
-			}
-		}
-		// Fallback
-		return exec.Command("x-terminal-emulator", "-e", fmt.Sprintf("bash -c %s", shellQuote(cmdStr)))
-	default:
-		return exec.Command(python, scriptPath)
+// startScript는 spec을 터미널 창에서, 또는 출력 콘솔 창과 함께 실행합니다.
+// 끝나면 rec에 결과를 채워 실행 기록에 남깁니다.
+func (l *LauncherApp) startScript(s ScriptItem, spec launch.Spec, rec history.Record) bool {
+	if !s.Header.Terminal() {
+		return l.startWithConsole(s, spec, rec)
+	}
+	run, termName, err := spec.StartTerminal()
+	if err != nil {
+		dialog.ShowError(err, l.Window)
+		return false
 	}
+	l.trackRun(s, run, termName, nil)
+
+	go func() {
+		err := run.Wait()
+		l.recordRun(rec, run, nil)
+		// Stop/Kill로 끝난 경우(-1)는 오류로 보지 않음
+		if err != nil && run.ExitCode() != -1 {
+			fyne.Do(func() { dialog.ShowError(err, l.Window) })
+		}
+	}()
+	return true
 }
 
 // 파일 위치 열기
@@ -703,21 +701,7 @@ func (l *LauncherApp) openFileLocationLinux(dir string) {
 
 // --- 임의 경로 스크립트 실행 ---
 func (l *LauncherApp) runScriptFromPath(path string) {
-	cat, iMac, iWin, iUbu, term, iDef := l.parseHeader(path)
-	item := ScriptItem{
-		Name:          strings.TrimSuffix(filepath.Base(path), ".py"),
-		Path:          path,
-		Category:      cat,
-		InterpMac:     iMac,
-		InterpWin:     iWin,
-		InterpUbuntu:  iUbu,
-		Terminal:      term,
-		InterpDefault: iDef,
-	}
-	cmd := l.runScript(item)
-	if cmd != nil {
-		fmt.Println("Launched external file:", path)
-	}
+	l.runScript(newScriptItem(path))
 }
 
 // --- 설정 및 데이터 관리 ---
@@ -727,6 +711,16 @@ func (l *LauncherApp) loadPreferences() {
 	l.FontSize = float32(l.App.Preferences().FloatWithFallback(KeyFontSize, 12))
 	l.UIScale = float32(l.App.Preferences().FloatWithFallback(KeyUIScale, 0.9))
 	l.ThemeMode = l.App.Preferences().StringWithFallback(KeyThemeMode, "system")
+	l.BackupOnSave = l.App.Preferences().BoolWithFallback(KeyBackupOnSave, false)
+	l.MetadataRunner = l.App.Preferences().StringWithFallback(KeyMetadataRunner, launch.DefaultMetadataRunner)
+	l.DefaultRunner = l.App.Preferences().StringWithFallback(KeyDefaultRunner, "python")
+	l.DefaultTimeout = l.App.Preferences().String(KeyDefaultTimeout)
+	l.MaxRuns = l.App.Preferences().IntWithFallback(KeyMaxRuns, 0)
+
+	l.Interpreters = map[string]string{}
+	if data := l.App.Preferences().String(KeyInterpreters); data != "" {
+		_ = json.Unmarshal([]byte(data), &l.Interpreters)
+	}
 
 	foldersJson := l.App.Preferences().String(KeyRegisteredFolders)
 	if foldersJson != "" {
@@ -740,9 +734,29 @@ func (l *LauncherApp) savePreferences() {
 	l.App.Preferences().SetFloat(KeyFontSize, float64(l.FontSize))
 	l.App.Preferences().SetFloat(KeyUIScale, float64(l.UIScale))
 	l.App.Preferences().SetString(KeyThemeMode, l.ThemeMode)
+	l.App.Preferences().SetBool(KeyBackupOnSave, l.BackupOnSave)
+	l.App.Preferences().SetString(KeyMetadataRunner, l.MetadataRunner)
+	l.App.Preferences().SetString(KeyDefaultRunner, l.DefaultRunner)
+	l.App.Preferences().SetString(KeyDefaultTimeout, l.DefaultTimeout)
+	l.App.Preferences().SetInt(KeyMaxRuns, l.MaxRuns)
 
 	data, _ := json.Marshal(l.RegisteredFolders)
 	l.App.Preferences().SetString(KeyRegisteredFolders, string(data))
+	data, _ = json.Marshal(l.Interpreters)
+	l.App.Preferences().SetString(KeyInterpreters, string(data))
+}
+
+// parseMaxRuns는 동시 실행 수 설정을 읽습니다. 비어 있거나 0이면 제한이 없습니다.
+func parseMaxRuns(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("max concurrent runs: expected a number of runs, got %q", s)
+	}
+	return n, nil
 }
 
 // 설정 다이얼로그 (새 창)
@@ -756,35 +770,34 @@ func (l *LauncherApp) showSettingsDialog() {
 	w := l.App.NewWindow("Settings")
 	l.SettingsWindow = w
 
-	pythonEntry := widget.NewEntry()
+	pythonEntry := widget.NewSelectEntry(nil)
 	pythonEntry.SetText(l.DefaultPythonPath)
 
+	// conda 환경을 선택지로 표시 (conda 호출이 느릴 수 있어 비동기)
+	go func() {
+		choices := launch.InterpreterChoices()
+		fyne.Do(func() { pythonEntry.SetOptions(choices) })
+	}()
+
 	// --- Auto Detect Logic ---
 	autoDetect := func(dir string) {
-		candidates := []string{
-			filepath.Join(dir, ".venv", "bin", "python"),
-			filepath.Join(dir, ".venv", "bin", "python3"),
-			filepath.Join(dir, "venv", "bin", "python"),
-			filepath.Join(dir, "venv", "bin", "python3"),
-			filepath.Join(dir, "env", "bin", "python"),
-		}
-		// Windows specific candidates
-		if runtime.GOOS == "windows" {
-			candidates = append(candidates,
-				filepath.Join(dir, ".venv", "Scripts", "python.exe"),
-				filepath.Join(dir, "venv", "Scripts", "python.exe"),
-				filepath.Join(dir, "env", "Scripts", "python.exe"),
-			)
-		}
-
 		found := ""
-		for _, c := range candidates {
-			if _, err := os.Stat(c); err == nil {
-				found = c
+		for _, name := range launch.VenvDirs {
+			if p := launch.VenvPython(filepath.Join(dir, name)); p != "" {
+				found = p
 				break
 			}
 		}
 
+		// environment.yml 의 conda 환경
+		if found == "" {
+			if name := launch.FindEnvironmentFile(dir); name != "" {
+				if env, err := launch.FindCondaEnv(name); err == nil {
+					found = env.Python()
+				}
+			}
+		}
+
 		// Go Project Detection
 		if found == "" {
 			if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
@@ -806,7 +819,7 @@ func (l *LauncherApp) showSettingsDialog() {
 		if found != "" {
 			pythonEntry.SetText(found)
 		} else {
-			dialog.ShowInformation("No Environment Found", "Could not find virtualenv, go.mod, or Package.swift in:\n"+dir, w)
+			dialog.ShowInformation("No Environment Found", "Could not find virtualenv, environment.yml, go.mod, or Package.swift in:\n"+dir, w)
 		}
 	}
 
@@ -826,8 +839,17 @@ func (l *LauncherApp) showSettingsDialog() {
 		}, w)
 	})
 
+	// 확인된 인터프리터만 저장 (창을 닫을 때)
+	validPython := l.DefaultPythonPath
+	pythonStatus := ui.WatchInterpreter(&pythonEntry.Entry, func(s string) {
+		validPython = s
+	})
+
 	// Layout for Interpreter Path
-	interpContainer := container.NewBorder(nil, nil, nil, container.NewHBox(pythonBtn, projBtn), pythonEntry)
+	interpContainer := container.NewVBox(
+		container.NewBorder(nil, nil, nil, container.NewHBox(pythonBtn, projBtn), pythonEntry),
+		pythonStatus,
+	)
 
 	fontSlider := widget.NewSlider(10, 24)
 	fontSlider.Step = 1
@@ -897,6 +919,72 @@ func (l *LauncherApp) showSettingsDialog() {
 		}, w)
 	})
 
+	// 이름 있는 인터프리터 (헤더에서 py=이름 으로 참조)
+	aliasNames := func() []string {
+		names := make([]string, 0, len(l.Interpreters))
+		for name := range l.Interpreters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	}
+	var aliasList *widget.List
+	aliasList = widget.NewList(
+		func() int { return len(l.Interpreters) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil,
+				widget.NewButtonWithIcon("", theme.DeleteIcon(), nil),
+				widget.NewLabel("template"),
+			)
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			c := o.(*fyne.Container)
+			label := c.Objects[0].(*widget.Label)
+			btn := c.Objects[1].(*widget.Button)
+
+			names := aliasNames()
+			if i >= len(names) {
+				return
+			}
+			name := names[i]
+			label.SetText(name + " → " + l.Interpreters[name])
+			btn.OnTapped = func() {
+				delete(l.Interpreters, name)
+				l.savePreferences()
+				aliasList.Refresh()
+			}
+		},
+	)
+	aliasScroll := container.NewVScroll(aliasList)
+	aliasScroll.SetMinSize(fyne.NewSize(0, 120))
+
+	aliasNameEntry := widget.NewEntry()
+	aliasNameEntry.SetPlaceHolder("Name, e.g. ds311")
+	aliasPathEntry := widget.NewEntry()
+	aliasPathEntry.SetPlaceHolder("Interpreter path")
+	aliasBrowseBtn := widget.NewButtonWithIcon("", theme.FileIcon(), func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err == nil && reader != nil {
+				aliasPathEntry.SetText(reader.URI().Path())
+			}
+		}, w)
+	})
+	addAliasBtn := widget.NewButtonWithIcon("Add", theme.ContentAddIcon(), func() {
+		name := strings.TrimSpace(aliasNameEntry.Text)
+		path := strings.TrimSpace(aliasPathEntry.Text)
+		ui.CheckNamedInterpreter(w, name, path, func() {
+			l.Interpreters[name] = path
+			l.savePreferences()
+			aliasNameEntry.SetText("")
+			aliasPathEntry.SetText("")
+			aliasList.Refresh()
+		})
+	})
+	aliasAddRow := container.NewGridWithColumns(2,
+		aliasNameEntry,
+		container.NewBorder(nil, nil, nil, container.NewHBox(aliasBrowseBtn, addAliasBtn), aliasPathEntry),
+	)
+
 	// Scale Control
 	scaleSlider := widget.NewSlider(0.5, 1.25)
 	scaleSlider.Step = 0.05
@@ -908,13 +996,54 @@ func (l *LauncherApp) showSettingsDialog() {
 	}
 	scaleContainer := container.NewBorder(nil, nil, nil, scaleLabel, scaleSlider)
 
+	// PEP 723 실행기: 뒤에 "스크립트 인자..."가 붙는 명령 (비우면 인터프리터로 실행)
+	runnerEntry := widget.NewEntry()
+	runnerEntry.SetText(l.MetadataRunner)
+	runnerEntry.SetPlaceHolder("empty = use the interpreter")
+	runnerEntry.Validator = checkMetadataRunner
+
+	// 기본 실행기: 내장 이름, conda:<env>, 또는 {script}가 들어간 템플릿
+	defaultRunnerEntry := widget.NewSelectEntry(launch.RunnerNames())
+	defaultRunnerEntry.SetText(l.DefaultRunner)
+	defaultRunnerEntry.SetPlaceHolder("python")
+
+	// 기본 제한 시간: 스크립트에 timeout= 이 없을 때 적용
+	timeoutEntry := widget.NewEntry()
+	timeoutEntry.SetText(l.DefaultTimeout)
+	timeoutEntry.SetPlaceHolder("none (e.g. 90s, 10m)")
+	timeoutEntry.Validator = func(s string) error {
+		_, err := pqr.ParseTimeout(s)
+		return err
+	}
+
+	// 동시 실행 수: 넘는 실행은 실행 목록 창의 대기열에서 기다림
+	maxRunsEntry := widget.NewEntry()
+	if l.MaxRuns > 0 {
+		maxRunsEntry.SetText(strconv.Itoa(l.MaxRuns))
+	}
+	maxRunsEntry.SetPlaceHolder("unlimited")
+	maxRunsEntry.Validator = func(s string) error {
+		_, err := parseMaxRuns(s)
+		return err
+	}
+
 	settingsForm := container.NewGridWithColumns(2,
 		widget.NewLabel("Interpreter Path:"), interpContainer,
+		widget.NewLabel("Default Runner:"), defaultRunnerEntry,
+		widget.NewLabel("PEP 723 Runner:"), runnerEntry,
+		widget.NewLabel("Default Timeout:"), timeoutEntry,
+		widget.NewLabel("Max Concurrent Runs:"), maxRunsEntry,
 		widget.NewLabel("UI Font Size:"), fontContainer,
 	)
 
+	backupCheck := widget.NewCheck("Keep a .bak copy when saving #pqr headers", func(b bool) {
+		l.BackupOnSave = b
+	})
+	backupCheck.SetChecked(l.BackupOnSave)
+
 	settingsItems := []fyne.CanvasObject{
 		settingsForm,
+		backupCheck,
 		widget.NewSeparator(),
 
 		widget.NewLabelWithStyle("UI Scale (Restart Required):", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
@@ -925,6 +1054,11 @@ func (l *LauncherApp) showSettingsDialog() {
 		fontContainer,
 		widget.NewSeparator(),
 
+		widget.NewLabelWithStyle("Named Interpreters (py=name):", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		aliasAddRow,
+		aliasScroll,
+		widget.NewSeparator(),
+
 		widget.NewLabelWithStyle("Registered Folders:", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 		addFolderBtn,
 		folderScroll,
@@ -945,7 +1079,28 @@ func (l *LauncherApp) showSettingsDialog() {
 	w.Resize(fyne.NewSize(500, 600))
 
 	w.SetOnClosed(func() {
-		l.DefaultPythonPath = pythonEntry.Text
+		l.DefaultPythonPath = validPython
+		if err := checkMetadataRunner(runnerEntry.Text); err == nil {
+			l.MetadataRunner = strings.TrimSpace(runnerEntry.Text)
+		} else {
+			dialog.ShowError(err, l.Window)
+		}
+		if _, err := launch.ParseRunner(defaultRunnerEntry.Text); err == nil {
+			l.DefaultRunner = strings.TrimSpace(defaultRunnerEntry.Text)
+		} else {
+			dialog.ShowError(err, l.Window)
+		}
+		if _, err := pqr.ParseTimeout(timeoutEntry.Text); err == nil {
+			l.DefaultTimeout = strings.TrimSpace(timeoutEntry.Text)
+		} else {
+			dialog.ShowError(err, l.Window)
+		}
+		if n, err := parseMaxRuns(maxRunsEntry.Text); err == nil {
+			l.MaxRuns = n
+			l.startQueued() // 제한이 늘었으면 기다리던 실행 시작
+		} else {
+			dialog.ShowError(err, l.Window)
+		}
 		l.savePreferences()
 		// 설정창 닫힐 때는 굳이 refresh를 강제할 필요는 없지만,
 		// python path가 바뀌었을 수 있으니 유지하겠습니다.
@@ -956,6 +1111,16 @@ func (l *LauncherApp) showSettingsDialog() {
 	w.Show()
 }
 
+// checkMetadataRunner는 PEP 723 실행기 설정값을 확인합니다. 빈 값은 인터프리터로
+// 직접 실행한다는 뜻이라 허용합니다.
+func checkMetadataRunner(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	_, err := launch.CommandRunner(s)
+	return err
+}
+
 // 속성 다이얼로그 표시
 func (l *LauncherApp) showPropertiesDialog(s ScriptItem) {
 	desc := widget.NewLabel("Script Path: " + s.Path)
@@ -982,12 +1147,36 @@ func (l *LauncherApp) showPropertiesDialog(s ScriptItem) {
 		return entry, row
 	}
 
-	macEntry, macRow := createBrowseRow("Path to python/sh (Mac)", s.InterpMac)
-	winEntry, winRow := createBrowseRow("Path to python/exe (Windows)", s.InterpWin)
-	ubuEntry, ubuRow := createBrowseRow("Path to python/sh (Ubuntu)", s.InterpUbuntu)
+	macEntry, macRow := createBrowseRow("Path to python/sh (Mac)", s.Header.Mac)
+	winEntry, winRow := createBrowseRow("Path to python/exe (Windows)", s.Header.Win)
+	ubuEntry, ubuRow := createBrowseRow("Path to python/sh (Ubuntu)", s.Header.Linux)
+
+	pyNames := make([]string, 0, len(l.Interpreters))
+	for name := range l.Interpreters {
+		pyNames = append(pyNames, name)
+	}
+	sort.Strings(pyNames)
+	pyEntry := widget.NewSelectEntry(pyNames)
+	pyEntry.SetPlaceHolder("Named interpreter")
+	pyEntry.SetText(s.Header.Py)
+
+	runnerEntry := widget.NewSelectEntry(launch.RunnerNames())
+	runnerEntry.SetPlaceHolder("Default runner")
+	runnerEntry.SetText(s.Header.Runner)
+
+	condaEntry := widget.NewSelectEntry(nil)
+	condaEntry.SetPlaceHolder("Conda environment")
+	condaEntry.SetText(s.Header.Conda)
+	go func() {
+		var names []string
+		for _, e := range launch.CondaEnvs() {
+			names = append(names, e.Name)
+		}
+		fyne.Do(func() { condaEntry.SetOptions(names) })
+	}()
 
 	termCheck := widget.NewCheck("Run in Terminal", nil)
-	termCheck.Checked = s.Terminal
+	termCheck.Checked = s.Header.Terminal()
 
 	form := &widget.Form{
 		Items: []*widget.FormItem{
@@ -995,16 +1184,64 @@ func (l *LauncherApp) showPropertiesDialog(s ScriptItem) {
 			{Text: "Mac", Widget: macRow},
 			{Text: "Win", Widget: winRow},
 			{Text: "Ubuntu", Widget: ubuRow},
+			{Text: "Py", Widget: pyEntry},
+			{Text: "Runner", Widget: runnerEntry},
+			{Text: "Conda", Widget: condaEntry},
 			{Text: "Option", Widget: termCheck},
 		},
 	}
 
+	// 실제로 사용될 인터프리터와 선택 규칙 (읽기 전용)
+	resolved := widget.NewLabel("Resolving...")
+	resolver := l.resolver()
+	go func() {
+		text := ""
+		if interp, err := resolver.Resolve(s.Header, runtime.GOOS, s.Path); err != nil {
+			text = err.Error()
+		} else {
+			text = fmt.Sprintf("%s (%s)", interp.Python, interp.Source)
+		}
+		fyne.Do(func() { resolved.SetText(text) })
+	}()
+	resolved.Wrapping = fyne.TextWrapBreak
+	form.Append("Interpreter", resolved)
+
+	// PEP 723 메타데이터 (읽기 전용)
+	if meta := s.Header.Script; meta != nil {
+		deps := widget.NewLabel("(none)")
+		if len(meta.Dependencies) > 0 {
+			deps.SetText(strings.Join(meta.Dependencies, "\n"))
+		}
+		deps.Wrapping = fyne.TextWrapBreak
+		if meta.RequiresPython != "" {
+			form.Append("Python", widget.NewLabel(meta.RequiresPython))
+		}
+		form.Append("Dependencies", deps)
+	}
+
 	content := container.NewVBox(desc, form)
 
+	// 헤더 문법 오류 또는 읽기 오류 표시
+	var syntaxErrs pqr.ErrorList
+	if errors.As(s.HeaderErr, &syntaxErrs) {
+		errBox := container.NewVBox(widget.NewLabelWithStyle("#pqr header problems (invalid parts are ignored):", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		for _, e := range syntaxErrs {
+			txt := canvas.NewText(e.Error(), theme.ErrorColor())
+			errBox.Add(txt)
+		}
+		content.Add(errBox)
+	} else if s.HeaderErr != nil {
+		content.Add(canvas.NewText("Could not read the #pqr header: "+s.HeaderErr.Error(), theme.ErrorColor()))
+	}
+
 	var popup *widget.PopUp
 
 	saveBtn := widget.NewButton("Save", func() {
-		l.updateScriptMetadata(s, catEntry.Text, macEntry.Text, winEntry.Text, ubuEntry.Text, termCheck.Checked)
+		if _, err := launch.ParseRunner(runnerEntry.Text); err != nil {
+			dialog.ShowError(err, l.Window)
+			return
+		}
+		l.updateScriptMetadata(s, catEntry.Text, macEntry.Text, winEntry.Text, ubuEntry.Text, strings.TrimSpace(pyEntry.Text), strings.TrimSpace(runnerEntry.Text), strings.TrimSpace(condaEntry.Text), termCheck.Checked)
 		l.refreshScripts()
 		if popup != nil {
 			popup.Hide()
@@ -1045,42 +1282,41 @@ func (l *LauncherApp) showPropertiesDialog(s ScriptItem) {
 }
 
 // 메타데이터 업데이트 (파일 쓰기)
-func (l *LauncherApp) updateScriptMetadata(s ScriptItem, cat, mac, win, ubuntu string, term bool) {
-	input, err := ioutil.ReadFile(s.Path)
+func (l *LauncherApp) updateScriptMetadata(s ScriptItem, cat, mac, win, ubuntu, py, runner, conda string, term bool) {
+	doc, err := pqr.ReadFile(s.Path)
 	if err != nil {
 		dialog.ShowError(err, l.Window)
 		return
 	}
 
-	lines := strings.Split(string(input), "\n")
-	var newLines []string
-	pqrFound := false
-
-	newPqr := fmt.Sprintf("#pqr cat=%s; mac=%s; win=%s; linux=%s; term=%t", cat, mac, win, ubuntu, term)
-
-	for _, line := range lines {
-		trim := strings.TrimSpace(line)
-		if strings.HasPrefix(trim, "#pqr") {
-			if !pqrFound {
-				newLines = append(newLines, newPqr)
-				pqrFound = true
-			}
-		} else {
-			newLines = append(newLines, line)
-		}
+	// 폼에서 바뀐 키만 수정 (def=, 알 수 없는 키, 주석, 순서는 그대로 유지)
+	if cat != s.Category {
+		doc.Set("cat", cat)
 	}
-
-	if !pqrFound {
-		if len(newLines) > 0 && strings.HasPrefix(strings.TrimSpace(newLines[0]), "#!") {
-			newLines = append(newLines[:1], newLines[0:]...)
-			newLines[1] = newPqr
-		} else {
-			newLines = append([]string{newPqr}, newLines...)
-		}
+	if mac != s.Header.Mac {
+		doc.Set("mac", mac)
+	}
+	if win != s.Header.Win {
+		doc.Set("win", win)
+	}
+	if ubuntu != s.Header.Linux {
+		doc.Set("linux", ubuntu)
+	}
+	if py != s.Header.Py {
+		doc.Set("py", py)
+	}
+	if runner != s.Header.Runner {
+		doc.Set("runner", runner)
+	}
+	if conda != s.Header.Conda {
+		doc.Set("conda", conda)
+	}
+	if term != s.Header.Terminal() {
+		doc.Set("term", strconv.FormatBool(term))
 	}
 
-	output := strings.Join(newLines, "\n")
-	if err := ioutil.WriteFile(s.Path, []byte(output), 0644); err != nil {
+	// 권한, 줄바꿈, BOM, 심볼릭 링크를 유지하며 원자적으로 저장
+	if err := doc.WriteFile(s.Path, l.BackupOnSave); err != nil {
 		dialog.ShowError(err, l.Window)
 	}
 }
@@ -1270,8 +1506,3 @@ func (w *ScriptWidget) TappedSecondary(e *fyne.PointEvent) {
 	)
 	widget.ShowPopUpMenuAtPosition(menu, w.app.Window.Canvas(), e.AbsolutePosition)
 }
-
-// shellQuote returns a shell-escaped version of the string.
-func shellQuote(s string) string {
-	return "'" + strings.ReplaceAll(s, "'", "'\\''") + "'"
-}
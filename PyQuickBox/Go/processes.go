@@ -0,0 +1,253 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"pqr"
+	"pqr/launch"
+)
+
+// runningScript는 런처가 시작해 아직 끝나지 않은 스크립트(또는 터미널 창)입니다.
+type runningScript struct {
+	Item     ScriptItem
+	Run      *launch.Run
+	Terminal string         // 터미널 이름, 직접 실행이면 ""
+	Console  *outputConsole // 직접 실행의 출력 창, 터미널 실행이면 nil
+}
+
+// queuedScript는 instance=queue 이거나 동시 실행 수 제한에 걸려 시작을 기다리는
+// 실행입니다.
+type queuedScript struct {
+	Item   ScriptItem
+	Policy string // instance= 정책
+	Queued time.Time
+	start  func() bool
+}
+
+// trackRun은 run을 실행 목록에 올리고, 끝나면 목록에서 내린 뒤 대기열에서
+// 시작할 수 있는 실행을 시작합니다. UI 스레드에서 호출해야 합니다.
+func (l *LauncherApp) trackRun(s ScriptItem, run *launch.Run, terminal string, console *outputConsole) {
+	rs := &runningScript{Item: s, Run: run, Terminal: terminal, Console: console}
+	l.Running = append(l.Running, rs)
+	l.refreshProcesses()
+
+	go func() {
+		<-run.Done()
+		fyne.Do(func() {
+			for i, r := range l.Running {
+				if r == rs {
+					l.Running = append(l.Running[:i], l.Running[i+1:]...)
+					break
+				}
+			}
+			l.startQueued()
+		})
+	}()
+}
+
+// schedule은 instance= 정책과 동시 실행 수 제한(MaxRuns)에 따라 start를 바로
+// 호출하거나, 대기열에 넣거나, (single) 이미 실행 중인 창을 보여 주고
+// 거절합니다. 대기열에 넣으면 true를 반환합니다. UI 스레드에서 호출해야 합니다.
+func (l *LauncherApp) schedule(s ScriptItem, start func() bool) bool {
+	policy := s.Header.InstancePolicy()
+	if policy == pqr.InstanceSingle {
+		if rs := l.runningOf(s.Path); rs != nil {
+			l.focusRun(rs)
+			return false
+		}
+		for _, q := range l.Queued {
+			if q.Item.Path == s.Path {
+				l.showProcessesDialog()
+				return false
+			}
+		}
+	}
+	if l.canStart(s.Path, policy) {
+		return start()
+	}
+	l.Queued = append(l.Queued, &queuedScript{Item: s, Policy: policy, Queued: time.Now(), start: start})
+	l.refreshProcesses()
+	return true
+}
+
+// canStart는 policy인 path 실행을 지금 시작할 수 있는지 알려 줍니다. 같은
+// 스크립트가 실행 중이거나 대기 중이면 parallel만 바로 시작합니다.
+func (l *LauncherApp) canStart(path, policy string) bool {
+	if l.MaxRuns > 0 && len(l.Running) >= l.MaxRuns {
+		return false
+	}
+	if policy == pqr.InstanceParallel {
+		return true
+	}
+	if l.runningOf(path) != nil {
+		return false
+	}
+	for _, q := range l.Queued {
+		if q.Item.Path == path {
+			return false
+		}
+	}
+	return true
+}
+
+// startQueued는 대기열을 앞에서부터 보며 시작할 수 있는 실행을 시작합니다.
+// 같은 스크립트가 끝나기를 기다리는 실행은 건너뛰고, 동시 실행 수 제한에
+// 걸리면 멈춥니다.
+func (l *LauncherApp) startQueued() {
+	for i := 0; i < len(l.Queued); {
+		if l.MaxRuns > 0 && len(l.Running) >= l.MaxRuns {
+			break
+		}
+		q := l.Queued[i]
+		if q.Policy != pqr.InstanceParallel && l.runningOf(q.Item.Path) != nil {
+			i++
+			continue
+		}
+		l.Queued = append(l.Queued[:i], l.Queued[i+1:]...)
+		q.start()
+	}
+	l.refreshProcesses()
+}
+
+// runningOf는 path 스크립트의 실행 중인 run을 찾습니다.
+func (l *LauncherApp) runningOf(path string) *runningScript {
+	for _, rs := range l.Running {
+		if rs.Item.Path == path {
+			return rs
+		}
+	}
+	return nil
+}
+
+// focusRun은 실행 중인 스크립트의 출력 창을 앞으로 가져오고, 터미널 실행이면
+// 이미 실행 중이라고 알립니다.
+func (l *LauncherApp) focusRun(rs *runningScript) {
+	if rs.Console != nil {
+		rs.Console.w.Show()
+		rs.Console.w.RequestFocus()
+		return
+	}
+	dialog.ShowInformation("Already Running",
+		fmt.Sprintf("%s is already running in %s (PID %d).\nIt is set to instance=single.", rs.Item.Name, rs.Terminal, rs.Run.Pid()),
+		l.Window)
+}
+
+// refreshProcesses는 상단 버튼의 실행 개수와 프로세스 창을 갱신합니다.
+func (l *LauncherApp) refreshProcesses() {
+	if l.ProcessesBtn != nil {
+		text := ""
+		if len(l.Running) > 0 || len(l.Queued) > 0 {
+			text = fmt.Sprint(len(l.Running))
+		}
+		if len(l.Queued) > 0 {
+			text += fmt.Sprintf(" +%d", len(l.Queued))
+		}
+		l.ProcessesBtn.SetText(text)
+	}
+	if l.ProcessList != nil {
+		l.ProcessList.Refresh()
+	}
+}
+
+// showProcessesDialog는 실행 중인 스크립트와 그 아래 대기열을 보여 주는 창을
+// 엽니다. Stop은 SIGTERM 후 남아 있으면 SIGKILL을, Kill은 바로 SIGKILL을
+// 프로세스 그룹 전체에 보냅니다. 대기 중인 실행은 Cancel로 뺄 수 있습니다.
+func (l *LauncherApp) showProcessesDialog() {
+	if l.ProcessWindow != nil {
+		l.ProcessWindow.Show()
+		l.ProcessWindow.RequestFocus()
+		return
+	}
+
+	w := l.App.NewWindow("Running Scripts")
+	l.ProcessWindow = w
+
+	l.ProcessList = widget.NewList(
+		func() int { return len(l.Running) + len(l.Queued) },
+		func() fyne.CanvasObject {
+			title := widget.NewLabelWithStyle("template", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+			command := widget.NewLabel("command")
+			command.Truncation = fyne.TextTruncateEllipsis
+			stopBtn := widget.NewButtonWithIcon("Stop", theme.MediaStopIcon(), nil)
+			killBtn := widget.NewButtonWithIcon("Kill", theme.CancelIcon(), nil)
+			killBtn.Importance = widget.DangerImportance
+			return container.NewBorder(nil, nil, nil,
+				container.NewHBox(stopBtn, killBtn),
+				container.NewVBox(title, command),
+			)
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			c := o.(*fyne.Container)
+			labels := c.Objects[0].(*fyne.Container)
+			buttons := c.Objects[1].(*fyne.Container)
+			stopBtn := buttons.Objects[0].(*widget.Button)
+			killBtn := buttons.Objects[1].(*widget.Button)
+
+			if i >= len(l.Running) {
+				if i-len(l.Running) >= len(l.Queued) {
+					return
+				}
+				q := l.Queued[i-len(l.Running)]
+				reason := "waiting for a free slot"
+				if q.Policy != pqr.InstanceParallel && l.runningOf(q.Item.Path) != nil {
+					reason = "waiting for the previous run"
+				}
+				labels.Objects[0].(*widget.Label).SetText(fmt.Sprintf("%s  ·  queued %s  ·  %s", q.Item.Name, q.Queued.Format("15:04:05"), reason))
+				labels.Objects[1].(*widget.Label).SetText(q.Item.Path)
+				stopBtn.SetText("Cancel")
+				stopBtn.OnTapped = func() {
+					for j, other := range l.Queued {
+						if other == q {
+							l.Queued = append(l.Queued[:j], l.Queued[j+1:]...)
+							break
+						}
+					}
+					l.refreshProcesses()
+				}
+				killBtn.Hide()
+				return
+			}
+			rs := l.Running[i]
+			stopBtn.SetText("Stop")
+			killBtn.Show()
+
+			title := fmt.Sprintf("%s  ·  PID %d  ·  started %s", rs.Item.Name, rs.Run.Pid(), rs.Run.Started.Format("15:04:05"))
+			if rs.Terminal != "" {
+				title += "  ·  " + rs.Terminal
+			}
+			labels.Objects[0].(*widget.Label).SetText(title)
+			labels.Objects[1].(*widget.Label).SetText(strings.Join(rs.Run.Args, " "))
+
+			stopBtn.OnTapped = func() {
+				if err := rs.Run.Stop(); err != nil {
+					dialog.ShowError(err, w)
+				}
+			}
+			killBtn.OnTapped = func() {
+				if err := rs.Run.Kill(); err != nil {
+					dialog.ShowError(err, w)
+				}
+			}
+		},
+	)
+
+	empty := widget.NewLabel("Scripts started from PyQuickBox appear here while they run, followed by runs waiting in the queue.")
+	empty.Wrapping = fyne.TextWrapWord
+	w.SetContent(container.NewBorder(empty, nil, nil, nil, l.ProcessList))
+	w.Resize(fyne.NewSize(640, 360))
+	w.SetOnClosed(func() {
+		l.ProcessWindow = nil
+		l.ProcessList = nil
+	})
+	w.Show()
+}
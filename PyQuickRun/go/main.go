@@ -3,11 +3,14 @@
 package main
 
 import (
-	"bufio"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,6 +23,11 @@ import (
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+
+	"pqr"
+	"pqr/history"
+	"pqr/launch"
+	"pqr/ui"
 )
 
 const AppName = "PyQuickRun"
@@ -28,11 +36,12 @@ func main() {
 	// 앱 생성
 	a := app.NewWithID("com.dinki.pyquickrun")
 	w := a.NewWindow(AppName + " - Linux Native")
-	w.Resize(fyne.NewSize(500, 400))
+	w.Resize(fyne.NewSize(500, 660))
 	w.SetFixedSize(true)
 
 	// --- 설정 로드 ---
 	prefs := a.Preferences()
+	runHistory := history.Open(filepath.Join(a.Storage().RootURI().Path(), "history"))
 	defaultPython := prefs.StringWithFallback("pythonPath", "/usr/bin/python3")
 
 	// --- UI 컴포넌트 ---
@@ -43,9 +52,41 @@ func main() {
 	pathEntry.SetText(defaultPython)
 	pathEntry.PlaceHolder = "e.g. /usr/bin/python3 or ~/venv/bin/python"
 
-	pathEntry.OnChanged = func(s string) {
+	// Saved only once the interpreter answers the probe
+	pathStatus := ui.WatchInterpreter(pathEntry, func(s string) {
 		prefs.SetString("pythonPath", s)
+		if launch.VenvOf(s) != "" {
+			rememberVenv(prefs, s)
+		}
+	})
+
+	// Interpreters found on this machine, labelled with version and source
+	// (probing them all takes a moment)
+	discovered := map[string]string{} // label -> interpreter
+	discoverSelect := widget.NewSelect(nil, func(label string) {
+		if python, ok := discovered[label]; ok {
+			pathEntry.SetText(python)
+		}
+	})
+	discover := func() {
+		discoverSelect.PlaceHolder = "Searching for interpreters..."
+		discoverSelect.ClearSelected()
+		go func() {
+			found := launch.Discover(loadRecentVenvs(prefs))
+			fyne.Do(func() {
+				discovered = map[string]string{}
+				labels := make([]string, len(found))
+				for i, d := range found {
+					labels[i] = d.String()
+					discovered[labels[i]] = d.Python
+				}
+				discoverSelect.PlaceHolder = fmt.Sprintf("Discovered interpreters (%d)", len(found))
+				discoverSelect.SetOptions(labels)
+			})
+		}()
 	}
+	discover()
+	rediscoverBtn := widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), discover)
 
 	browseBtn := widget.NewButtonWithIcon("Binary", theme.FileIcon(), func() {
 		fileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
@@ -59,13 +100,49 @@ func main() {
 	projBtn := widget.NewButtonWithIcon("Project", theme.FolderIcon(), func() {
 		folderDialog := dialog.NewFolderOpen(func(list fyne.ListableURI, err error) {
 			if err == nil && list != nil {
-				// autoDetect logic moved here or defined locally
-				autoDetect(list.Path(), pathEntry, statusLabel, w)
+				autoDetect(list.Path(), prefs, statusLabel, w)
 			}
 		}, w)
 		folderDialog.Show()
 	})
 
+	namesBtn := widget.NewButtonWithIcon("Names", theme.ListIcon(), func() {
+		showInterpretersDialog(prefs, w)
+	})
+
+	projectsBtn := widget.NewButtonWithIcon("Projects", theme.FolderOpenIcon(), func() {
+		showProjectsDialog(prefs, w)
+	})
+
+	// Default runner: built-in name, conda:<env> or a template with {script};
+	// saved only when it parses, runs use the saved one
+	runnerEntry := widget.NewSelectEntry(launch.RunnerNames())
+	runnerEntry.SetText(prefs.StringWithFallback("defaultRunner", "python"))
+	runnerEntry.SetPlaceHolder("python")
+	runnerEntry.Validator = func(s string) error {
+		_, err := launch.ParseRunner(s)
+		return err
+	}
+	runnerEntry.OnChanged = func(s string) {
+		if _, err := launch.ParseRunner(s); err == nil {
+			prefs.SetString("defaultRunner", strings.TrimSpace(s))
+		}
+	}
+
+	// Default timeout for scripts without timeout=; saved only when valid
+	timeoutEntry := widget.NewEntry()
+	timeoutEntry.SetText(prefs.String("defaultTimeout"))
+	timeoutEntry.SetPlaceHolder("none (e.g. 90s, 10m)")
+	timeoutEntry.Validator = func(s string) error {
+		_, err := pqr.ParseTimeout(s)
+		return err
+	}
+	timeoutEntry.OnChanged = func(s string) {
+		if _, err := pqr.ParseTimeout(s); err == nil {
+			prefs.SetString("defaultTimeout", strings.TrimSpace(s))
+		}
+	}
+
 	chkTerminal := widget.NewCheck("Run in Terminal window", func(b bool) {
 		prefs.SetBool("useTerminal", b)
 	})
@@ -76,33 +153,80 @@ func main() {
 	})
 	chkClose.SetChecked(prefs.BoolWithFallback("closeOnSuccess", false))
 
+	// Output of non-terminal runs
+	output := newOutputPane()
+	var currentRun *launch.Run // latest run; the Stop button ends it
+	var stopRun func()
+	stopBtn := widget.NewButtonWithIcon("Stop", theme.MediaStopIcon(), func() {
+		if stopRun != nil {
+			stopRun()
+		}
+	})
+	stopBtn.Disable()
+
+	// Input for the current run's stdin; EOF closes it
+	var sendInput func(string)
+	var closeInput func()
+	inputEntry := widget.NewEntry()
+	inputEntry.SetPlaceHolder("Input for the script (Enter sends a line)")
+	inputEntry.OnSubmitted = func(line string) {
+		if sendInput != nil {
+			inputEntry.SetText("")
+			sendInput(line + "\n")
+		}
+	}
+	inputEntry.Disable()
+	eofBtn := widget.NewButton("EOF", func() {
+		if closeInput != nil {
+			closeInput()
+		}
+	})
+	eofBtn.Disable()
+	disableInput := func() {
+		sendInput, closeInput = nil, nil
+		inputEntry.Disable()
+		eofBtn.Disable()
+	}
+
 	// --- 실행 로직 ---
-	var runScript func(string, *PqrHeader, *bool, *bool)
+	var runScript func(string, *pqr.Header, *bool, *bool)
+
+	// Latest run of each script, for instance=single and instance=queue
+	active := map[string]*launch.Run{}
+	runningRun := func(path string) *launch.Run {
+		if run := active[path]; run != nil && !isDone(run) {
+			return run
+		}
+		return nil
+	}
+	trackRun := func(path string, run *launch.Run) {
+		active[path] = run
+		go func() {
+			<-run.Done()
+			fyne.Do(func() {
+				if active[path] == run {
+					delete(active, path)
+				}
+			})
+		}()
+	}
+
+	historyBtn := widget.NewButtonWithIcon("History", theme.HistoryIcon(), func() {
+		showHistoryDialog(runHistory, w, func(path string) { runScript(path, nil, nil, nil) })
+	})
 
 	saveAndRunGo := func(scriptPath string, terminal bool, category string) {
-		file, err := os.ReadFile(scriptPath)
+		doc, err := pqr.ReadFile(scriptPath)
 		if err != nil {
+			statusLabel.SetText("Error reading script: " + err.Error())
 			return
 		}
-		lines := strings.Split(string(file), "\n")
-		var tagParts []string
-		tagParts = append(tagParts, fmt.Sprintf("term=%v", terminal))
-		if strings.TrimSpace(category) != "" {
-			tagParts = append(tagParts, fmt.Sprintf("cat=%s", category))
-		}
-		headerTag := "#pqr " + strings.Join(tagParts, "; ")
-
-		insertIdx := 0
-		if len(lines) > 0 && strings.HasPrefix(lines[0], "#!") {
-			insertIdx = 1
+		doc.Set("term", strconv.FormatBool(terminal))
+		if c := strings.TrimSpace(category); c != "" {
+			doc.Set("cat", c)
 		}
 
-		newLines := make([]string, 0, len(lines)+1)
-		newLines = append(newLines, lines[:insertIdx]...)
-		newLines = append(newLines, headerTag)
-		newLines = append(newLines, lines[insertIdx:]...)
-
-		err = os.WriteFile(scriptPath, []byte(strings.Join(newLines, "\n")), 0644)
+		err = doc.WriteFile(scriptPath, prefs.BoolWithFallback("backupOnSave", false))
 		if err != nil {
 			statusLabel.SetText("Error saving header: " + err.Error())
 			return
@@ -120,6 +244,11 @@ func main() {
 		closeCheck := widget.NewCheck("Close window after successful execution", nil)
 		closeCheck.SetChecked(prefs.BoolWithFallback("closeOnSuccess", false))
 
+		backupCheck := widget.NewCheck("Keep a .bak copy when saving", func(b bool) {
+			prefs.SetBool("backupOnSave", b)
+		})
+		backupCheck.SetChecked(prefs.BoolWithFallback("backupOnSave", false))
+
 		form := container.NewVBox(
 			container.NewCenter(container.NewPadded(widget.NewLabelWithStyle("No #pqr header found", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}))),
 			widget.NewLabel("Category:"),
@@ -128,6 +257,7 @@ func main() {
 			widget.NewLabel("Next time this script will:"),
 			termCheck,
 			closeCheck,
+			backupCheck,
 			layout.NewSpacer(),
 			widget.NewLabelWithStyle("Shortcuts: Run Now (Ctrl+D) / Save & Run (Ctrl+S)", fyne.TextAlignCenter, fyne.TextStyle{Italic: true}),
 		)
@@ -157,195 +287,301 @@ func main() {
 		))
 
 		dia = dialog.NewCustom("Notice", "Cancel", dialogContent, w)
-		dia.Resize(fyne.NewSize(450, 420))
+		dia.Resize(fyne.NewSize(450, 450))
 		dia.Show()
 	}
 
-	runScript = func(scriptPath string, headerOverride *PqrHeader, terminalOverride *bool, closeOverride *bool) {
+	runScript = func(scriptPath string, headerOverride *pqr.Header, terminalOverride *bool, closeOverride *bool) {
 		if abs, err := filepath.Abs(scriptPath); err == nil {
 			scriptPath = abs
 		}
 
-		pythonBin := pathEntry.Text
 		useTerm := chkTerminal.Checked
 		closeWin := chkClose.Checked
 		scriptDir := filepath.Dir(scriptPath)
-		workDir := scriptDir
-		sourceMsg := "Default"
 
-		var header PqrHeader
+		var header pqr.Header
 		if headerOverride != nil {
 			header = *headerOverride
 		} else {
-			header = scanPqrHeaderGo(scriptPath)
+			var err error
+			header, err = pqr.ParseFile(scriptPath)
+			var syntaxErrs pqr.ErrorList
+			if errors.As(err, &syntaxErrs) {
+				statusLabel.SetText("#pqr header has errors: " + filepath.Base(scriptPath))
+				msg := make([]string, 0, len(syntaxErrs))
+				for _, e := range syntaxErrs {
+					msg = append(msg, e.Error())
+				}
+				dialog.ShowConfirm("Invalid #pqr Header",
+					strings.Join(msg, "\n")+"\n\nRun with the valid settings anyway?",
+					func(ok bool) {
+						if ok {
+							runScript(scriptPath, &header, terminalOverride, closeOverride)
+						}
+					}, w)
+				return
+			}
 		}
 
-		if !header.HasPqr && terminalOverride == nil {
+		if !header.Found && terminalOverride == nil {
 			showOptionDialog(scriptPath)
 			return
 		}
 
-		foundInterpreter := ""
-		if header.Interpreter != "" {
-			foundInterpreter = header.Interpreter
-			pythonBin = foundInterpreter
-			sourceMsg = "#qpr"
+		// instance=: single refuses and queue waits while the script still runs
+		if prev := runningRun(scriptPath); prev != nil {
+			switch header.InstancePolicy() {
+			case pqr.InstanceSingle:
+				statusLabel.SetText(fmt.Sprintf("%s is already running (PID %d)", filepath.Base(scriptPath), prev.Pid()))
+				return
+			case pqr.InstanceQueue:
+				statusLabel.SetText(fmt.Sprintf("Queued %s until the current run exits", filepath.Base(scriptPath)))
+				go func() {
+					<-prev.Done()
+					fyne.Do(func() { runScript(scriptPath, &header, terminalOverride, closeOverride) })
+				}()
+				return
+			}
 		}
 
-		// Search for .venv (Upward)
-		projectRoot := ""
-		tempDir := scriptDir
-		for i := 0; i < 5; i++ {
-			candidates := []string{
-				filepath.Join(tempDir, ".venv"),
-				filepath.Join(tempDir, "venv"),
-			}
-			for _, c := range candidates {
-				if info, err := os.Stat(c); err == nil && info.IsDir() {
-					binCandidates := []string{
-						filepath.Join(c, "bin", "python"),
-						filepath.Join(c, "bin", "python3"),
-					}
-					for _, bc := range binCandidates {
-						if binfo, berr := os.Stat(bc); berr == nil && !binfo.IsDir() {
-							if foundInterpreter == "" {
-								pythonBin = bc
-								sourceMsg = "Auto(.venv)"
-							}
-							projectRoot = tempDir
-							break
-						}
-					}
-				}
-				if projectRoot != "" {
-					break
-				}
+		// Interpreter: #pqr > venv found above the script > project folder > default
+		// Runner: runner= > #pqr interpreter > PEP 723 runner > default runner
+		runner, err := launch.SelectRunner(header, runtime.GOOS, prefs.StringWithFallback("defaultRunner", "python"), prefs.StringWithFallback("metadataRunner", launch.DefaultMetadataRunner))
+		if err != nil {
+			statusLabel.SetText("Error: " + err.Error())
+			return
+		}
+
+		resolver := launch.Resolver{
+			Default:        prefs.StringWithFallback("pythonPath", "/usr/bin/python3"), // last value that passed the probe
+			Aliases:        loadInterpreters(prefs),
+			Projects:       loadProjects(prefs),
+			SkipConstraint: !runner.UsesPython(),
+		}
+		// Probing candidates can take seconds, so resolve off the UI thread
+		startResolved := func(interp launch.Interpreter, err error) {
+			var aliasErr *launch.UnknownAliasError
+			if errors.As(err, &aliasErr) {
+				statusLabel.SetText("Error: " + err.Error())
+				ui.OfferInterpreterMapping(w, aliasErr.Name, func(python string) {
+					m := loadInterpreters(prefs)
+					m[aliasErr.Name] = python
+					saveInterpreters(prefs, m)
+					runScript(scriptPath, &header, terminalOverride, closeOverride)
+				})
+				return
 			}
-			if projectRoot != "" {
-				break
+			var noInterp *launch.NoInterpreterError
+			if errors.As(err, &noInterp) {
+				statusLabel.SetText("Error: " + err.Error())
+				dialog.ShowError(fmt.Errorf("%s: %v", filepath.Base(scriptPath), err), w)
+				return
 			}
-			parent := filepath.Dir(tempDir)
-			if parent == tempDir {
-				break
+			if err != nil {
+				statusLabel.SetText("Error: " + err.Error())
+				return
+			}
+			sourceMsg := interp.Source
+			if interp.Venv != "" && runner.UsesPython() {
+				rememberVenv(prefs, interp.Python)
 			}
-			tempDir = parent
-		}
 
-		venvDir := ""
-		absBin, _ := filepath.Abs(pythonBin)
-		binDir := filepath.Dir(absBin)
-		if _, err := os.Stat(filepath.Join(filepath.Dir(binDir), "pyvenv.cfg")); err == nil {
-			venvDir = filepath.Dir(binDir)
-			if projectRoot != "" {
-				workDir = projectRoot
+			if header.Term != nil {
+				useTerm = *header.Term
+			}
+			if terminalOverride != nil {
+				useTerm = *terminalOverride
+			}
+			if closeOverride != nil {
+				closeWin = *closeOverride
 			}
-		}
 
-		if foundInterpreter != "" && !filepath.IsAbs(foundInterpreter) {
-			pythonBin = filepath.Join(scriptDir, foundInterpreter)
-		}
+			flags, err := header.InterpreterFlags()
+			if err != nil {
+				statusLabel.SetText("Error: pyflags: " + err.Error())
+				return
+			}
+			args, err := header.ScriptArgs()
+			if err != nil {
+				statusLabel.SetText("Error: args: " + err.Error())
+				return
+			}
 
-		if header.TermOverride != nil {
-			useTerm = *header.TermOverride
-		}
-		if terminalOverride != nil {
-			useTerm = *terminalOverride
-		}
-		if closeOverride != nil {
-			closeWin = *closeOverride
-		}
+			// Working directory: script folder unless cwd= says otherwise
+			workDir, err := header.WorkDir(scriptPath)
+			if err != nil {
+				statusLabel.SetText("Error: " + err.Error())
+				return
+			}
+			// stdin=file:<path> feeds a file; otherwise the input field is stdin
+			stdinFile, err := header.StdinFile(scriptPath)
+			if err != nil {
+				statusLabel.SetText("Error: " + err.Error())
+				return
+			}
+			// Timeout: timeout= > default setting; terminal runs are not timed
+			timeout, err := launch.Timeout(header, prefs.String("defaultTimeout"))
+			if err != nil {
+				statusLabel.SetText("Error: " + err.Error())
+				return
+			}
 
-		statusLabel.SetText(fmt.Sprintf("Running %s via %s", filepath.Base(scriptPath), sourceMsg))
-
-		if useTerm {
-			envPrefix := ""
-			if venvDir != "" {
-				envPrefix = fmt.Sprintf("export VIRTUAL_ENV=%s; export PATH=%s:$PATH; ", shellQuote(venvDir), shellQuote(binDir))
-			}
-			cmdStr := fmt.Sprintf("%scd %s && %s %s; echo; echo 'Exit Code: $?'; read -p 'Press Enter to exit...'",
-				envPrefix, shellQuote(workDir), shellQuote(pythonBin), shellQuote(scriptPath))
-
-			terminals := [][]string{
-				{"ptyxis", "--", "bash", "-c"},
-				{"kgx", "--", "bash", "-c"},
-				{"gnome-terminal", "--", "bash", "-c"},
-				{"konsole", "-e", "bash", "-c"},
-				{"xfce4-terminal", "-x", "bash", "-c"},
-				{"xterm", "-e", "bash", "-c"},
-			}
-
-			launched := false
-			for _, term := range terminals {
-				if _, err := exec.LookPath(term[0]); err == nil {
-					args := append(term[1:], cmdStr)
-					exec.Command(term[0], args...).Start()
-					statusLabel.SetText("Launched in " + term[0])
-					launched = true
-					if closeWin {
-						w.Close()
-					}
-					break
-				}
+			argv, err := runner.Argv(interp.Python, flags, scriptPath, args)
+			if err != nil {
+				statusLabel.SetText("Error: " + err.Error())
+				return
+			}
+			if !runner.UsesPython() {
+				// The runner picks its own environment
+				sourceMsg = runner.Name
+				interp.Venv = ""
 			}
 
-			if !launched {
-				appleScript := fmt.Sprintf(`tell application "Terminal" to do script "%s"`, cmdStr)
-				if _, err := exec.LookPath("osascript"); err == nil {
-					exec.Command("osascript", "-e", appleScript).Start()
-					statusLabel.SetText("Launched in macOS Terminal")
-					launched = true
-					if closeWin {
-						w.Close()
-					}
-				}
+			spec := launch.Spec{
+				Argv: argv,
+				Dir:  workDir,
+				Env:  append([]string{"PYTHONUNBUFFERED=1"}, interp.Environ()...),
+
+				Timeout: timeout,
+				Stdin:   stdinFile,
+			}
+			headerEnv, err := header.Environ(scriptDir)
+			if err != nil {
+				statusLabel.SetText("Error: " + err.Error())
+				return
 			}
+			spec.Env = append(spec.Env, headerEnv...)
 
-			if !launched {
-				if _, err := exec.LookPath("x-terminal-emulator"); err == nil {
-					exec.Command("x-terminal-emulator", "-e", "bash", "-c", cmdStr).Start()
-					statusLabel.SetText("Launched in x-terminal-emulator")
-					launched = true
-					if closeWin {
-						w.Close()
-					}
-				} else {
-					statusLabel.SetText("Error: No supported terminal found.")
-				}
+			statusLabel.SetText(fmt.Sprintf("Running %s via %s", filepath.Base(scriptPath), sourceMsg))
+
+			rec := history.Record{
+				Script:      scriptPath,
+				Interpreter: fmt.Sprintf("%s (%s)", interp.Python, sourceMsg),
+				Argv:        argv,
+				Args:        args,
+				Dir:         workDir,
+				Terminal:    useTerm,
+			}
+			if !runner.UsesPython() {
+				rec.Interpreter = sourceMsg
 			}
 
-		} else {
-			cmd := exec.Command(pythonBin, scriptPath)
-			cmd.Dir = workDir
-			cmd.Env = os.Environ()
-			if venvDir != "" {
-				cmd.Env = append(cmd.Env, "VIRTUAL_ENV="+venvDir)
-				pathFound := false
-				for i, env := range cmd.Env {
-					if strings.HasPrefix(strings.ToUpper(env), "PATH=") {
-						cmd.Env[i] = "PATH=" + binDir + ":" + env[5:]
-						pathFound = true
-						break
-					}
+			if useTerm {
+				run, termName, err := spec.StartTerminal()
+				if errors.Is(err, launch.ErrNoTerminal) {
+					statusLabel.SetText("Error: No supported terminal found.")
+					return
 				}
-				if !pathFound {
-					cmd.Env = append(cmd.Env, "PATH="+binDir+":"+os.Getenv("PATH"))
+				if err != nil {
+					statusLabel.SetText("Error: " + err.Error())
+					return
 				}
-			}
-
-			output, err := cmd.CombinedOutput()
-			if err == nil {
-				statusLabel.SetText("Success (Exit Code 0)")
+				trackRun(scriptPath, run)
+				go func() {
+					run.Wait()
+					recordRun(runHistory, rec, run, nil)
+				}()
+				statusLabel.SetText("Launched in " + termName)
 				if closeWin {
+					w.Close()
+				}
+			} else {
+				// One captured run at a time, so the pane, Stop and the input field
+				// always belong to a live run; a second one waits for it
+				if prev := currentRun; prev != nil && !isDone(prev) {
+					statusLabel.SetText(fmt.Sprintf("Queued %s until the current run exits", filepath.Base(scriptPath)))
 					go func() {
-						time.Sleep(time.Second)
-						w.Close()
+						<-prev.Done()
+						fyne.Do(func() { runScript(scriptPath, &header, terminalOverride, closeOverride) })
 					}()
+					return
 				}
-			} else {
-				statusLabel.SetText("Failed: " + err.Error())
-				dialog.ShowInformation("Execution Error", string(output), w)
+
+				// Run in the background; output and status come back through fyne.Do
+				output.Clear()
+				capture := &history.Capture{}
+				var run *launch.Run
+				run, err = spec.Start(func(stream launch.Stream, data []byte) {
+					capture.Write(stream == launch.Stderr, data)
+					text := string(data)
+					fyne.Do(func() {
+						if currentRun == run {
+							output.Write(stream, text)
+						}
+					})
+				})
+				if err != nil {
+					statusLabel.SetText("Error: " + err.Error())
+					return
+				}
+				trackRun(scriptPath, run)
+				stopped := false
+				currentRun = run
+				stopRun = func() {
+					stopped = true
+					if err := run.Stop(); err != nil {
+						statusLabel.SetText("Error stopping script: " + err.Error())
+					}
+				}
+				stopBtn.Enable()
+
+				// A pipe does not echo, so sent lines are shown and kept here.
+				// Writes happen off the UI thread in case the script is not reading.
+				disableInput()
+				stdin := launch.NewStdinWriter(run, func(err error) {
+					fyne.Do(func() { statusLabel.SetText("Input not sent: " + err.Error()) })
+				})
+				if stdin != nil {
+					sendInput = func(text string) {
+						output.Write(launch.Stdout, text)
+						capture.Write(false, []byte(text))
+						stdin.Write(text)
+					}
+					closeInput = func() {
+						stdin.Close()
+						disableInput()
+					}
+					inputEntry.Enable()
+					eofBtn.Enable()
+				}
+
+				go func() {
+					err := run.Wait()
+					recordRun(runHistory, rec, run, capture)
+					d := run.Duration().Round(time.Millisecond)
+					fyne.Do(func() {
+						if currentRun != run {
+							return // a newer run owns the status line
+						}
+						currentRun, stopRun = nil, nil
+						stopBtn.Disable()
+						disableInput()
+						switch {
+						case run.TimedOut():
+							statusLabel.SetText(fmt.Sprintf("Timed out after %s", d))
+						case stopped:
+							statusLabel.SetText(fmt.Sprintf("Stopped after %s", d))
+						case err == nil:
+							statusLabel.SetText(fmt.Sprintf("Success (Exit Code 0, %s)", d))
+							if closeWin {
+								go func() {
+									time.Sleep(time.Second)
+									fyne.Do(w.Close)
+								}()
+							}
+						default:
+							statusLabel.SetText(fmt.Sprintf("Failed: %v (%s)", err, d))
+						}
+					})
+				}()
 			}
 		}
+		statusLabel.SetText("Resolving interpreter for " + filepath.Base(scriptPath) + "...")
+		go func() {
+			interp, err := resolver.Resolve(header, runtime.GOOS, scriptPath)
+			fyne.Do(func() { startResolved(interp, err) })
+		}()
 	}
 
 	// --- 드래그 앤 드롭 ---
@@ -353,7 +589,7 @@ func main() {
 		if len(uris) > 0 {
 			path := uris[0].Path()
 			if fi, err := os.Stat(path); err == nil && fi.IsDir() {
-				autoDetect(path, pathEntry, statusLabel, w)
+				autoDetect(path, prefs, statusLabel, w)
 			} else if strings.HasSuffix(strings.ToLower(path), ".py") {
 				runScript(path, nil, nil, nil)
 			} else {
@@ -380,14 +616,23 @@ func main() {
 		container.NewCenter(widget.NewLabelWithStyle(AppName, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})),
 		widget.NewSeparator(),
 		container.NewPadded(container.NewVBox(
-			widget.NewLabel("Interpreter Path (uv or python):"),
-			container.NewBorder(nil, nil, nil, container.NewHBox(browseBtn, projBtn), pathEntry),
+			widget.NewLabel("Interpreter Path:"),
+			container.NewBorder(nil, nil, nil, container.NewHBox(browseBtn, projBtn, namesBtn), pathEntry),
+			container.NewBorder(nil, nil, nil, container.NewHBox(rediscoverBtn, projectsBtn), discoverSelect),
+			pathStatus,
+			container.NewGridWithColumns(2,
+				container.NewBorder(nil, nil, widget.NewLabel("Runner:"), nil, runnerEntry),
+				container.NewBorder(nil, nil, widget.NewLabel("Timeout:"), nil, timeoutEntry),
+			),
 			container.NewVBox(chkTerminal, chkClose),
 		)),
 		container.NewPadded(dropCard),
+		container.NewPadded(container.NewBorder(nil,
+			container.NewBorder(nil, nil, nil, eofBtn, inputEntry),
+			nil, nil, output.object())),
 		layout.NewSpacer(),
 		widget.NewSeparator(),
-		statusLabel,
+		container.NewBorder(nil, nil, nil, container.NewHBox(historyBtn, stopBtn), statusLabel),
 		container.NewHBox(layout.NewSpacer(), widget.NewLabelWithStyle("© 2026 DINKIssTyle", fyne.TextAlignTrailing, fyne.TextStyle{Italic: true})),
 	)
 
@@ -412,7 +657,7 @@ func main() {
 				if strings.HasSuffix(strings.ToLower(targetPath), ".py") {
 					go func() {
 						time.Sleep(200 * time.Millisecond)
-						runScript(targetPath, nil, nil, nil)
+						fyne.Do(func() { runScript(targetPath, nil, nil, nil) })
 					}()
 				}
 			}
@@ -422,80 +667,356 @@ func main() {
 	w.ShowAndRun()
 }
 
-// autoDetect logic
-func autoDetect(dir string, pathEntry *widget.Entry, statusLabel *widget.Label, w fyne.Window) {
-	candidates := []string{
-		filepath.Join(dir, ".venv", "bin", "python"),
-		filepath.Join(dir, ".venv", "bin", "python3"),
-		filepath.Join(dir, "venv", "bin", "python"),
-		filepath.Join(dir, "venv", "bin", "python3"),
-		filepath.Join(dir, "env", "bin", "python"),
+// autoDetect maps the project folder dir to the venv or conda environment
+// found in it. Without one, it offers to map dir to the current interpreter.
+func autoDetect(dir string, prefs fyne.Preferences, statusLabel *widget.Label, w fyne.Window) {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	mapTo := func(python string) {
+		m := loadProjects(prefs)
+		m[dir] = python
+		saveProjects(prefs, m)
+		statusLabel.SetText("Project " + filepath.Base(dir) + ": " + python)
 	}
 
 	found := ""
-	for _, c := range candidates {
-		if _, err := os.Stat(c); err == nil {
-			found = c
+	for _, name := range launch.VenvDirs {
+		if p := launch.VenvPython(filepath.Join(dir, name)); p != "" {
+			found = p
 			break
 		}
 	}
+	// Conda environment named in environment.yml
+	if found == "" {
+		if name := launch.FindEnvironmentFile(dir); name != "" {
+			if env, err := launch.FindCondaEnv(name); err == nil {
+				found = env.Python()
+			}
+		}
+	}
 
 	if found != "" {
-		pathEntry.SetText(found)
-		statusLabel.SetText("Auto-selected: " + found)
-	} else {
-		statusLabel.SetText("No venv found in: " + filepath.Base(dir))
-		dialog.ShowInformation("No Venv Found", "Could not find standard virtualenv (bin/python) in:\n"+dir, w)
+		if launch.VenvOf(found) != "" {
+			rememberVenv(prefs, found)
+		}
+		mapTo(found)
+		return
+	}
+
+	current := strings.TrimSpace(prefs.StringWithFallback("pythonPath", "/usr/bin/python3"))
+	if current == "" {
+		current = launch.DefaultPython()
 	}
+	statusLabel.SetText("No venv found in: " + filepath.Base(dir))
+	msg := "Could not find a virtualenv (bin/python) or conda environment.yml in:\n" + dir + "\n\nUse the current interpreter for scripts in this folder?\n" + current
+	dialog.ShowConfirm("No Venv Found", msg, func(ok bool) {
+		if ok {
+			mapTo(current)
+		}
+	}, w)
 }
 
-// shellQuote returns a shell-escaped version of the string.
-func shellQuote(s string) string {
-	return "'" + strings.ReplaceAll(s, "'", "'\\''") + "'"
+// loadProjects returns the project directories mapped to interpreters.
+func loadProjects(prefs fyne.Preferences) map[string]string {
+	m := map[string]string{}
+	if data := prefs.String("projectInterpreters"); data != "" {
+		_ = json.Unmarshal([]byte(data), &m)
+	}
+	return m
 }
 
-type PqrHeader struct {
-	Interpreter  string
-	TermOverride *bool
-	Category     string
-	HasPqr       bool
+func saveProjects(prefs fyne.Preferences, m map[string]string) {
+	data, _ := json.Marshal(m)
+	prefs.SetString("projectInterpreters", string(data))
 }
 
-func scanPqrHeaderGo(scriptPath string) PqrHeader {
-	var header PqrHeader
-	file, err := os.Open(scriptPath)
+// showProjectsDialog lists the project directories mapped to interpreters
+// and removes mappings.
+func showProjectsDialog(prefs fyne.Preferences, w fyne.Window) {
+	m := loadProjects(prefs)
+	dirs := func() []string {
+		list := make([]string, 0, len(m))
+		for dir := range m {
+			list = append(list, dir)
+		}
+		sort.Strings(list)
+		return list
+	}
+
+	var list *widget.List
+	list = widget.NewList(
+		func() int { return len(m) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil,
+				widget.NewButtonWithIcon("", theme.DeleteIcon(), nil),
+				widget.NewLabel("template"),
+			)
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			c := o.(*fyne.Container)
+			label := c.Objects[0].(*widget.Label)
+			btn := c.Objects[1].(*widget.Button)
+
+			all := dirs()
+			if i >= len(all) {
+				return
+			}
+			dir := all[i]
+			label.SetText(dir + " → " + m[dir])
+			btn.OnTapped = func() {
+				delete(m, dir)
+				saveProjects(prefs, m)
+				list.Refresh()
+			}
+		},
+	)
+	scroll := container.NewVScroll(list)
+	scroll.SetMinSize(fyne.NewSize(0, 150))
+
+	hint := widget.NewLabel("Scripts below a folder use its interpreter unless #pqr or a venv picks one. Add folders with the Project button or by dropping them on the window.")
+	hint.Wrapping = fyne.TextWrapWord
+	content := container.NewBorder(hint, nil, nil, nil, scroll)
+	d := dialog.NewCustom("Project Interpreters", "Close", content, w)
+	d.Resize(fyne.NewSize(460, 320))
+	d.Show()
+}
+
+// loadInterpreters returns the named interpreters (py=name) from prefs.
+func loadInterpreters(prefs fyne.Preferences) map[string]string {
+	m := map[string]string{}
+	if data := prefs.String("interpreters"); data != "" {
+		_ = json.Unmarshal([]byte(data), &m)
+	}
+	return m
+}
+
+func saveInterpreters(prefs fyne.Preferences, m map[string]string) {
+	data, _ := json.Marshal(m)
+	prefs.SetString("interpreters", string(data))
+}
+
+// isDone reports whether run has exited.
+func isDone(run *launch.Run) bool {
+	select {
+	case <-run.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// recordRun fills in how run ended and saves rec with the captured output
+// (nil for terminal runs) to the run history.
+func recordRun(store *history.Store, rec history.Record, run *launch.Run, capture *history.Capture) {
+	rec.Started = run.Started
+	rec.Ended = run.Started.Add(run.Duration())
+	rec.ExitCode = run.ExitCode()
+	rec.TimedOut = run.TimedOut()
+	var chunks []history.Chunk
+	if capture != nil {
+		chunks, rec.Truncated = capture.Chunks()
+	}
+	if err := store.Add(&rec, chunks); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving run history: %v\n", err)
+	}
+}
+
+// showHistoryDialog lists the most recent runs with their outcome. Output
+// shows what a run printed; Re-run calls rerun with the script.
+func showHistoryDialog(store *history.Store, w fyne.Window, rerun func(string)) {
+	records, err := store.List(50)
 	if err != nil {
-		return header
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	for i := 0; i < 20 && scanner.Scan(); i++ {
-		line := strings.TrimSpace(scanner.Text())
-		if strings.HasPrefix(strings.ToLower(line), "#pqr") {
-			header.HasPqr = true
-			remainder := strings.TrimSpace(strings.TrimPrefix(strings.ToLower(line), "#pqr"))
-			parts := strings.Split(remainder, ";")
-			for _, part := range parts {
-				kv := strings.Split(strings.TrimSpace(part), "=")
-				if len(kv) == 2 {
-					key := strings.TrimSpace(kv[0])
-					val := strings.TrimSpace(kv[1])
-					if key == "linux" && val != "" {
-						header.Interpreter = val
-					} else if key == "cat" {
-						header.Category = val
-					} else if key == "term" {
-						b := false
-						if val == "true" || val == "1" || val == "yes" {
-							b = true
-						}
-						header.TermOverride = &b
-					}
-				}
+		dialog.ShowError(err, w)
+		return
+	}
+
+	var d dialog.Dialog
+	list := widget.NewList(
+		func() int { return len(records) },
+		func() fyne.CanvasObject {
+			detail := widget.NewLabel("template")
+			detail.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, nil,
+				container.NewHBox(
+					widget.NewButtonWithIcon("", theme.DocumentIcon(), nil),
+					widget.NewButtonWithIcon("", theme.MediaReplayIcon(), nil),
+				),
+				detail,
+			)
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			rec := records[i]
+			c := o.(*fyne.Container)
+			label := c.Objects[0].(*widget.Label)
+			buttons := c.Objects[1].(*fyne.Container)
+
+			outcome := fmt.Sprintf("exit %d", rec.ExitCode)
+			switch {
+			case rec.Terminal:
+				outcome = "terminal"
+			case rec.TimedOut:
+				outcome = "timed out"
+			case rec.ExitCode == -1:
+				outcome = "stopped"
+			}
+			label.SetText(fmt.Sprintf("%s  %s  %s, %s", rec.Started.Format("01-02 15:04"), filepath.Base(rec.Script), outcome, rec.Duration().Round(time.Millisecond)))
+			label.Importance = widget.MediumImportance
+			switch {
+			case rec.TimedOut:
+				label.Importance = widget.WarningImportance
+			case rec.ExitCode != 0 && !rec.Terminal:
+				label.Importance = widget.DangerImportance
 			}
-			return header
+			label.Refresh()
+
+			outputBtn := buttons.Objects[0].(*widget.Button)
+			outputBtn.OnTapped = func() { showRunOutput(store, rec, w) }
+			if rec.Terminal {
+				outputBtn.Disable()
+			} else {
+				outputBtn.Enable()
+			}
+			buttons.Objects[1].(*widget.Button).OnTapped = func() {
+				d.Hide()
+				rerun(rec.Script)
+			}
+		},
+	)
+
+	d = dialog.NewCustom("Recent Runs", "Close", list, w)
+	d.Resize(fyne.NewSize(480, 360))
+	d.Show()
+}
+
+// showRunOutput shows the output saved for rec.
+func showRunOutput(store *history.Store, rec *history.Record, w fyne.Window) {
+	chunks, err := store.Output(rec.ID)
+	if err != nil {
+		dialog.ShowError(err, w)
+		return
+	}
+	pane := newOutputPane()
+	if rec.Truncated {
+		pane.Write(launch.Stderr, fmt.Sprintf("[output truncated to the last %d KiB]\n", history.MaxOutput/1024))
+	}
+	for _, chunk := range chunks {
+		stream := launch.Stdout
+		if chunk.Stderr {
+			stream = launch.Stderr
 		}
+		pane.Write(stream, chunk.Text)
 	}
-	return header
+	info := widget.NewLabel(strings.Join(rec.Argv, " "))
+	info.Truncation = fyne.TextTruncateEllipsis
+	d := dialog.NewCustom("Output: "+filepath.Base(rec.Script), "Close", container.NewBorder(info, nil, nil, nil, pane.object()), w)
+	d.Resize(fyne.NewSize(480, 380))
+	d.Show()
+}
+
+// maxRecentVenvs is how many venv interpreters the discovery list remembers.
+const maxRecentVenvs = 10
+
+// loadRecentVenvs returns the interpreters of recently used venvs, newest
+// first.
+func loadRecentVenvs(prefs fyne.Preferences) []string {
+	var list []string
+	if data := prefs.String("recentVenvs"); data != "" {
+		_ = json.Unmarshal([]byte(data), &list)
+	}
+	return list
+}
+
+// rememberVenv moves python to the front of the recent venvs.
+func rememberVenv(prefs fyne.Preferences, python string) {
+	if abs, err := filepath.Abs(python); err == nil {
+		python = abs
+	}
+	list := []string{python}
+	for _, p := range loadRecentVenvs(prefs) {
+		if p != python && len(list) < maxRecentVenvs {
+			list = append(list, p)
+		}
+	}
+	data, _ := json.Marshal(list)
+	prefs.SetString("recentVenvs", string(data))
+}
+
+// showInterpretersDialog edits the named interpreters that headers refer to
+// with py=name.
+func showInterpretersDialog(prefs fyne.Preferences, w fyne.Window) {
+	m := loadInterpreters(prefs)
+	names := func() []string {
+		list := make([]string, 0, len(m))
+		for name := range m {
+			list = append(list, name)
+		}
+		sort.Strings(list)
+		return list
+	}
+
+	var list *widget.List
+	list = widget.NewList(
+		func() int { return len(m) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil,
+				widget.NewButtonWithIcon("", theme.DeleteIcon(), nil),
+				widget.NewLabel("template"),
+			)
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			c := o.(*fyne.Container)
+			label := c.Objects[0].(*widget.Label)
+			btn := c.Objects[1].(*widget.Button)
+
+			all := names()
+			if i >= len(all) {
+				return
+			}
+			name := all[i]
+			label.SetText(name + " → " + m[name])
+			btn.OnTapped = func() {
+				delete(m, name)
+				saveInterpreters(prefs, m)
+				list.Refresh()
+			}
+		},
+	)
+	scroll := container.NewVScroll(list)
+	scroll.SetMinSize(fyne.NewSize(0, 150))
+
+	nameEntry := widget.NewEntry()
+	nameEntry.PlaceHolder = "Name, e.g. ds311"
+	pathEntry := widget.NewEntry()
+	pathEntry.PlaceHolder = "Interpreter path"
+	browseBtn := widget.NewButtonWithIcon("", theme.FileIcon(), func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err == nil && reader != nil {
+				pathEntry.SetText(reader.URI().Path())
+			}
+		}, w)
+	})
+	addBtn := widget.NewButtonWithIcon("Add", theme.ContentAddIcon(), func() {
+		name := strings.TrimSpace(nameEntry.Text)
+		path := strings.TrimSpace(pathEntry.Text)
+		ui.CheckNamedInterpreter(w, name, path, func() {
+			m[name] = path
+			saveInterpreters(prefs, m)
+			nameEntry.SetText("")
+			pathEntry.SetText("")
+			list.Refresh()
+		})
+	})
+
+	content := container.NewBorder(
+		container.NewVBox(
+			widget.NewLabel("Scripts refer to these with #pqr py=name;"),
+			container.NewGridWithColumns(2, nameEntry, container.NewBorder(nil, nil, nil, container.NewHBox(browseBtn, addBtn), pathEntry)),
+		),
+		nil, nil, nil,
+		scroll,
+	)
+	d := dialog.NewCustom("Named Interpreters", "Close", content, w)
+	d.Resize(fyne.NewSize(460, 320))
+	d.Show()
 }
@@ -0,0 +1,34 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package main
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+
+	"pqr/ui"
+)
+
+// maxOutputRows is how many lines the output pane keeps; older lines are
+// dropped.
+const maxOutputRows = 5000
+
+// outputPane shows the output of the current non-terminal run as it is
+// written (see ui.Output).
+type outputPane struct {
+	*ui.Output
+}
+
+func newOutputPane() *outputPane {
+	return &outputPane{ui.NewOutput(maxOutputRows)}
+}
+
+// object returns the pane with room for a few lines.
+func (p *outputPane) object() fyne.CanvasObject {
+	spacer := canvas.NewRectangle(color.Transparent)
+	spacer.SetMinSize(fyne.NewSize(0, 120))
+	return container.NewStack(spacer, p.Grid)
+}
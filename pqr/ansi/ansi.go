@@ -0,0 +1,207 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+// Package ansi splits script output into text spans styled by ANSI SGR
+// escape sequences (colors, bold, italic), for the output consoles of
+// PyQuickRun and PyQuickBox. Other escape sequences are dropped.
+package ansi
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// Style is the look of a span. Nil colors mean the console's default.
+type Style struct {
+	FG, BG  color.Color
+	Bold    bool
+	Italic  bool
+	Inverse bool
+}
+
+// Span is text written with one style.
+type Span struct {
+	Text  string
+	Style Style
+}
+
+// Palette holds the 16 basic colors: black, red, green, yellow, blue,
+// magenta, cyan, white, then their bright variants.
+var Palette = [16]color.RGBA{
+	{0x00, 0x00, 0x00, 0xff}, {0xcd, 0x31, 0x31, 0xff}, {0x0d, 0xbc, 0x79, 0xff}, {0xe5, 0xe5, 0x10, 0xff},
+	{0x24, 0x72, 0xc8, 0xff}, {0xbc, 0x3f, 0xbc, 0xff}, {0x11, 0xa8, 0xcd, 0xff}, {0xe5, 0xe5, 0xe5, 0xff},
+	{0x66, 0x66, 0x66, 0xff}, {0xf1, 0x4c, 0x4c, 0xff}, {0x23, 0xd1, 0x8b, 0xff}, {0xf5, 0xf5, 0x43, 0xff},
+	{0x3b, 0x8e, 0xea, 0xff}, {0xd6, 0x70, 0xd6, 0xff}, {0x29, 0xb8, 0xdb, 0xff}, {0xff, 0xff, 0xff, 0xff},
+}
+
+// Color256 returns color n of the xterm 256-color palette.
+func Color256(n int) color.Color {
+	switch {
+	case n < 16:
+		return Palette[n]
+	case n < 232:
+		n -= 16
+		level := func(v int) uint8 {
+			if v == 0 {
+				return 0
+			}
+			return uint8(55 + v*40)
+		}
+		return color.RGBA{level(n / 36), level(n / 6 % 6), level(n % 6), 0xff}
+	default:
+		v := uint8(8 + (n-232)*10)
+		return color.RGBA{v, v, v, 0xff}
+	}
+}
+
+// Parser turns the chunks of one output stream into spans. It keeps the
+// current style and an escape sequence cut off at the end of a chunk for the
+// next call, so one Parser must be used per stream.
+type Parser struct {
+	style   Style
+	pending string
+}
+
+// Parse returns the spans in chunk, merging text of the same style.
+func (p *Parser) Parse(chunk string) []Span {
+	s := p.pending + chunk
+	p.pending = ""
+	var spans []Span
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			spans = append(spans, Span{Text: text.String(), Style: p.style})
+			text.Reset()
+		}
+	}
+	for i := 0; i < len(s); {
+		if s[i] != 0x1b {
+			j := strings.IndexByte(s[i:], 0x1b)
+			if j < 0 {
+				j = len(s) - i
+			}
+			text.WriteString(s[i : i+j])
+			i += j
+			continue
+		}
+		n, params, final, ok := escapeSequence(s[i:])
+		if !ok {
+			p.pending = s[i:]
+			break
+		}
+		if final == 'm' {
+			flush()
+			p.apply(params)
+		}
+		i += n
+	}
+	flush()
+	return spans
+}
+
+// escapeSequence measures the escape sequence at the start of s. For CSI
+// sequences it returns the parameters and final byte. ok is false when s
+// ends before the sequence does.
+func escapeSequence(s string) (n int, params string, final byte, ok bool) {
+	if len(s) < 2 {
+		return 0, "", 0, false
+	}
+	switch s[1] {
+	case '[': // CSI: parameters and intermediates, then a final byte
+		for i := 2; i < len(s); i++ {
+			if c := s[i]; c >= 0x40 && c <= 0x7e {
+				return i + 1, s[2:i], c, true
+			}
+		}
+		return 0, "", 0, false
+	case ']': // OSC: ends with BEL or ESC \
+		for i := 2; i < len(s); i++ {
+			if s[i] == 0x07 {
+				return i + 1, "", 0, true
+			}
+			if s[i] == 0x1b && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2, "", 0, true
+			}
+		}
+		return 0, "", 0, false
+	}
+	return 2, "", 0, true
+}
+
+// apply updates the style for the parameters of an SGR sequence.
+func (p *Parser) apply(params string) {
+	var codes []int
+	for _, f := range strings.FieldsFunc(params, func(r rune) bool { return r == ';' || r == ':' }) {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return
+		}
+		codes = append(codes, n)
+	}
+	if len(codes) == 0 {
+		codes = []int{0}
+	}
+	for i := 0; i < len(codes); i++ {
+		switch c := codes[i]; {
+		case c == 0:
+			p.style = Style{}
+		case c == 1:
+			p.style.Bold = true
+		case c == 3:
+			p.style.Italic = true
+		case c == 7:
+			p.style.Inverse = true
+		case c == 22:
+			p.style.Bold = false
+		case c == 23:
+			p.style.Italic = false
+		case c == 27:
+			p.style.Inverse = false
+		case c >= 30 && c <= 37:
+			p.style.FG = Palette[c-30]
+		case c >= 90 && c <= 97:
+			p.style.FG = Palette[c-90+8]
+		case c == 39:
+			p.style.FG = nil
+		case c >= 40 && c <= 47:
+			p.style.BG = Palette[c-40]
+		case c >= 100 && c <= 107:
+			p.style.BG = Palette[c-100+8]
+		case c == 49:
+			p.style.BG = nil
+		case c == 38 || c == 48:
+			col, used := extendedColor(codes[i+1:])
+			i += used
+			if col == nil {
+				continue
+			}
+			if c == 38 {
+				p.style.FG = col
+			} else {
+				p.style.BG = col
+			}
+		}
+	}
+}
+
+// extendedColor reads "5;n" or "2;r;g;b" after a 38 or 48 code and returns
+// the color and how many codes it used.
+func extendedColor(codes []int) (color.Color, int) {
+	if len(codes) >= 2 && codes[0] == 5 && codes[1] >= 0 && codes[1] < 256 {
+		return Color256(codes[1]), 2
+	}
+	if len(codes) >= 4 && codes[0] == 2 {
+		return color.RGBA{uint8(codes[1]), uint8(codes[2]), uint8(codes[3]), 0xff}, 4
+	}
+	return nil, len(codes)
+}
+
+// Strip returns s without escape sequences.
+func Strip(s string) string {
+	var p Parser
+	var b strings.Builder
+	for _, span := range p.Parse(s) {
+		b.WriteString(span.Text)
+	}
+	return b.String()
+}
@@ -0,0 +1,82 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package pqr
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// SplitArgs splits s into words the way a POSIX shell would, without
+// expanding variables: whitespace separates words, '...' is literal, "..."
+// honours \" \\ \$ and \`, and a backslash outside quotes escapes the next
+// character. An unquoted word starting with "~/" (or just "~") gets the home
+// directory.
+func SplitArgs(s string) ([]string, error) {
+	var words []string
+	var b strings.Builder
+	inWord := false // a word has started, even if it is still empty ("")
+	tilde := false  // the current word starts with an unquoted ~
+
+	flush := func() {
+		if !inWord {
+			return
+		}
+		w := b.String()
+		if tilde && (w == "~" || strings.HasPrefix(w, "~/")) {
+			if home, err := os.UserHomeDir(); err == nil {
+				w = home + w[1:]
+			}
+		}
+		words = append(words, w)
+		b.Reset()
+		inWord, tilde = false, false
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			flush()
+		case c == '\'':
+			inWord = true
+			j := strings.IndexByte(s[i+1:], '\'')
+			if j < 0 {
+				return nil, errors.New("unterminated ' in arguments")
+			}
+			b.WriteString(s[i+1 : i+1+j])
+			i += j + 1
+		case c == '"':
+			inWord = true
+			closed := false
+			for i++; i < len(s); i++ {
+				if s[i] == '"' {
+					closed = true
+					break
+				}
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`", s[i+1]) >= 0 {
+					i++
+				}
+				b.WriteByte(s[i])
+			}
+			if !closed {
+				return nil, errors.New(`unterminated " in arguments`)
+			}
+		case c == '\\':
+			inWord = true
+			if i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			}
+		default:
+			if !inWord && c == '~' {
+				tilde = true
+			}
+			inWord = true
+			b.WriteByte(c)
+		}
+	}
+	flush()
+	return words, nil
+}
@@ -0,0 +1,297 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package pqr
+
+import (
+	"regexp"
+	"strings"
+)
+
+// BOM is the UTF-8 byte order mark some Windows editors put before "#!".
+const BOM = "\ufeff"
+
+// Document is the text of a script prepared for header edits. Set and Delete
+// rewrite only the entries they touch; every other line, unknown key,
+// comment, legacy line and the key order are left exactly as they were.
+type Document struct {
+	Lines   []string // split on "\n"; a CRLF line keeps its "\r"
+	BOM     bool     // the text started with a UTF-8 BOM
+	Newline string   // "\r\n" or "\n", used for inserted lines
+}
+
+// NewDocument splits src into lines and detects its BOM and line ending.
+func NewDocument(src string) *Document {
+	d := &Document{Newline: "\n"}
+	if strings.HasPrefix(src, BOM) {
+		d.BOM = true
+		src = src[len(BOM):]
+	}
+	if crlf := strings.Count(src, "\r\n"); crlf > 0 && crlf >= strings.Count(src, "\n")-crlf {
+		d.Newline = "\r\n"
+	}
+	d.Lines = strings.Split(src, "\n")
+	return d
+}
+
+// String returns the full text, BOM included.
+func (d *Document) String() string {
+	s := strings.Join(d.Lines, "\n")
+	if d.BOM {
+		s = BOM + s
+	}
+	return s
+}
+
+// Header parses the document's header.
+func (d *Document) Header() (Header, error) {
+	return Parse(strings.NewReader(d.String()))
+}
+
+// docEntry is an entry located in Lines. start/valStart/end are byte offsets
+// into the line; for legacy lines only line is meaningful.
+type docEntry struct {
+	Entry
+	line                 int
+	start, valStart, end int
+	legacy               bool
+	op                   bool // `key>=value` form, see scannedEntry
+}
+
+// layout is where the header lives in a Document.
+type layout struct {
+	entries    []docEntry
+	lastSingle int // last "#pqr key=val" line, -1 if none
+	lastLegacy int // last legacy line, -1 if none
+	blockEnd   int // line before which a new block entry goes, -1 if no block
+}
+
+func (d *Document) layout() layout {
+	lay := layout{lastSingle: -1, lastLegacy: -1, blockEnd: -1}
+	inBlock := false
+	for n, line := range d.Lines {
+		kind := classify(line, inBlock)
+		if inBlock && (kind == lineOther || kind == lineSingle || kind == lineBlockStart) {
+			lay.blockEnd = n // unterminated: append after the last body line
+		}
+		switch kind {
+		case lineBlockStart:
+			inBlock = true
+			lay.blockEnd = n + 1
+		case lineBlockEnd:
+			inBlock = false
+			lay.blockEnd = n
+		case lineBlockComment:
+			lay.blockEnd = n + 1
+		case lineBlockBody:
+			lay.blockEnd = n + 1
+			trimmed := strings.TrimLeft(line, " \t")
+			off := len(line) - len(trimmed) + 1
+			entries, _ := scanEntries(strings.TrimRight(trimmed[1:], " \t\r"), off+1)
+			lay.add(n, entries)
+		case lineSingle:
+			inBlock = false
+			trimmed := strings.TrimLeft(line, " \t")
+			off := len(line) - len(trimmed) + 4
+			if e, ok := legacyEntry(trimmed[4:]); ok {
+				lay.entries = append(lay.entries, docEntry{Entry: e, line: n, legacy: true})
+				lay.lastLegacy = n
+				continue
+			}
+			entries, _ := scanEntries(strings.TrimRight(trimmed[4:], " \t\r"), off+1)
+			lay.add(n, entries)
+			lay.lastSingle = n
+		default:
+			inBlock = false
+		}
+	}
+	return lay
+}
+
+func (lay *layout) add(line int, entries []scannedEntry) {
+	for _, e := range entries {
+		lay.entries = append(lay.entries, docEntry{
+			Entry:    e.Entry,
+			line:     line,
+			start:    e.col - 1,
+			valStart: e.valCol - 1,
+			end:      e.endCol - 1,
+			op:       e.op,
+		})
+	}
+}
+
+// find returns the last entry for key, which is the one that takes effect.
+func (lay layout) find(key string) (docEntry, bool) {
+	for i := len(lay.entries) - 1; i >= 0; i-- {
+		if canonicalKey(lay.entries[i].Key) == key {
+			return lay.entries[i], true
+		}
+	}
+	return docEntry{}, false
+}
+
+// findEnv returns the last env= entry for the variable value sets.
+func (lay layout) findEnv(value string) (docEntry, bool) {
+	for i := len(lay.entries) - 1; i >= 0; i-- {
+		e := lay.entries[i]
+		if canonicalKey(e.Key) == "env" && envName(e.Value) == envName(value) {
+			return e, true
+		}
+	}
+	return docEntry{}, false
+}
+
+// envName returns the variable name of an env= value ("KEY=VALUE").
+func envName(value string) string {
+	name, _, _ := strings.Cut(value, "=")
+	return strings.TrimSpace(name)
+}
+
+// Set changes key to value. An existing entry is rewritten in place; a new
+// key is added to the last block or #pqr line, and a script without a header
+// gets a new block. An empty value deletes the key.
+//
+// env= may repeat, so for "env" the entry of the same variable is rewritten
+// and a new variable gets an entry of its own.
+func (d *Document) Set(key, value string) {
+	key = canonicalKey(key)
+	if value == "" {
+		d.Delete(key)
+		return
+	}
+	lay := d.layout()
+	find := lay.find
+	if key == "env" {
+		find = func(string) (docEntry, bool) { return lay.findEnv(value) }
+	}
+	if e, ok := find(key); ok {
+		if e.Value == value {
+			return
+		}
+		line := d.Lines[e.line]
+		if e.legacy {
+			cr := line[len(strings.TrimSuffix(line, "\r")):]
+			d.Lines[e.line] = legacyLine(line, e.Key, value) + cr
+		} else {
+			v := Quote(value)
+			if e.op && strings.IndexByte("<>!~", v[0]) < 0 {
+				v = "=" + v // `py>=3.11` becoming `py=ds311`
+			}
+			d.Lines[e.line] = line[:e.valStart] + v + line[e.end:]
+		}
+		return
+	}
+
+	entry := formatEntry(key, value)
+	switch {
+	case lay.blockEnd >= 0:
+		d.insert(lay.blockEnd, "# "+entry)
+	case lay.lastSingle >= 0:
+		line := d.Lines[lay.lastSingle]
+		trimmed := strings.TrimRight(line, " \t\r")
+		sep := "; "
+		if strings.HasSuffix(trimmed, ";") {
+			sep = " "
+		}
+		d.Lines[lay.lastSingle] = trimmed + sep + entry + line[len(trimmed):]
+	case lay.lastLegacy >= 0:
+		d.insert(lay.lastLegacy+1, "#pqr "+entry)
+	default:
+		d.insert(d.headerPos(), BlockStart, "# "+entry, BlockEnd)
+	}
+}
+
+// legacyLine rewrites a legacy line, keeping its form when value allows it.
+func legacyLine(line, key, value string) string {
+	indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	if key == "terminal" {
+		if b, ok := parseBool(value); ok {
+			if b {
+				return indent + "#pqr terminal true"
+			}
+			return indent + "#pqr terminal false"
+		}
+	} else if !strings.ContainsAny(value, "\"\n\r") {
+		return indent + "#pqr " + key + ` "` + value + `"`
+	}
+	return indent + "#pqr " + formatEntry(key, value)
+}
+
+// Delete removes every entry for key. A #pqr line or block line left without
+// entries is removed as well.
+func (d *Document) Delete(key string) {
+	key = canonicalKey(key)
+	for {
+		e, ok := d.layout().find(key)
+		if !ok {
+			return
+		}
+		if e.legacy {
+			d.remove(e.line)
+			continue
+		}
+
+		line := d.Lines[e.line]
+		from, to := e.start, e.end
+		after := skipSpaceFrom(line, to)
+		if after < len(line) && line[after] == ';' {
+			to = skipSpaceFrom(line, after+1)
+		} else {
+			before := from
+			for before > 0 && isSpace(line[before-1]) {
+				before--
+			}
+			if before > 0 && line[before-1] == ';' {
+				from = before - 1
+			}
+		}
+		line = line[:from] + line[to:]
+
+		rest := strings.TrimLeft(line, " \t")
+		if isPqrLine(rest) {
+			rest = rest[4:]
+		} else {
+			rest = strings.TrimPrefix(rest, "#")
+		}
+		if strings.Trim(rest, " \t\r;") == "" {
+			d.remove(e.line)
+		} else {
+			d.Lines[e.line] = line
+		}
+	}
+}
+
+func skipSpaceFrom(s string, i int) int {
+	for i < len(s) && isSpace(s[i]) {
+		i++
+	}
+	return i
+}
+
+// codingRe matches a PEP 263 encoding declaration.
+var codingRe = regexp.MustCompile(`^[ \t\f]*#.*?coding[:=][ \t]*[-_.a-zA-Z0-9]+`)
+
+// headerPos is where a new header goes: after the shebang and encoding lines.
+func (d *Document) headerPos() int {
+	at := 0
+	if at < len(d.Lines) && strings.HasPrefix(d.Lines[at], "#!") {
+		at++
+	}
+	if at < len(d.Lines) && codingRe.MatchString(d.Lines[at]) {
+		at++
+	}
+	return at
+}
+
+func (d *Document) insert(at int, lines ...string) {
+	if d.Newline == "\r\n" {
+		for i := range lines {
+			lines[i] += "\r"
+		}
+	}
+	d.Lines = append(d.Lines[:at], append(lines, d.Lines[at:]...)...)
+}
+
+func (d *Document) remove(at int) {
+	d.Lines = append(d.Lines[:at], d.Lines[at+1:]...)
+}
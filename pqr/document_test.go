@@ -0,0 +1,219 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package pqr
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDocumentSet(t *testing.T) {
+	tests := []struct {
+		name       string
+		src        string
+		key, value string
+		want       string
+	}{
+		{
+			name: "rewrite in place",
+			src:  "#pqr cat=Old; term=true\nprint(1)\n",
+			key:  "cat", value: "New",
+			want: "#pqr cat=New; term=true\nprint(1)\n",
+		},
+		{
+			name: "quote when needed",
+			src:  "#pqr win=python\n",
+			key:  "win", value: `C:\tools\new;dir\python.exe`,
+			want: "#pqr win='C:\\tools\\new;dir\\python.exe'\n",
+		},
+		{
+			name: "append to last line",
+			src:  "#pqr cat=Tools\nprint(1)\n",
+			key:  "term", value: "true",
+			want: "#pqr cat=Tools; term=true\nprint(1)\n",
+		},
+		{
+			name: "append to block",
+			src:  "#pqr\n# cat=Tools\n#/pqr\n",
+			key:  "py", value: ">=3.11",
+			want: "#pqr\n# cat=Tools\n# py>=3.11\n#/pqr\n",
+		},
+		{
+			name: "block comments kept",
+			src:  "#pqr\n# Nightly report\n# cat=A\n# owner: ops team\n#/pqr\n",
+			key:  "term", value: "true",
+			want: "#pqr\n# Nightly report\n# cat=A\n# owner: ops team\n# term=true\n#/pqr\n",
+		},
+		{
+			name: "new block after shebang and coding",
+			src:  "#!/usr/bin/env python3\n# -*- coding: utf-8 -*-\nprint(1)\n",
+			key:  "cat", value: "Tools",
+			want: "#!/usr/bin/env python3\n# -*- coding: utf-8 -*-\n#pqr\n# cat=Tools\n#/pqr\nprint(1)\n",
+		},
+		{
+			name: "BOM and CRLF kept",
+			src:  BOM + "#!python\r\nprint(1)\r\n",
+			key:  "cat", value: "Tools",
+			want: BOM + "#!python\r\n#pqr\r\n# cat=Tools\r\n#/pqr\r\nprint(1)\r\n",
+		},
+		{
+			name: "CRLF line rewritten",
+			src:  "#pqr cat=A\r\nprint(1)\r\n",
+			key:  "cat", value: "B",
+			want: "#pqr cat=B\r\nprint(1)\r\n",
+		},
+		{
+			name: "legacy line keeps its form",
+			src:  "#pqr cat \"Old\"\n",
+			key:  "cat", value: "New Tools",
+			want: "#pqr cat \"New Tools\"\n",
+		},
+		{
+			name: "legacy terminal alias",
+			src:  "#pqr terminal true\r\n",
+			key:  "term", value: "false",
+			want: "#pqr terminal false\r\n",
+		},
+		{
+			name: "legacy line with quote switches form",
+			src:  "#pqr def \"python3\"\n",
+			key:  "def", value: `"hi"`,
+			want: "#pqr def='\"hi\"'\n",
+		},
+		{
+			name: "op form to constraint",
+			src:  "#pqr py>=3.11; cat=A\n",
+			key:  "py", value: "<3.13",
+			want: "#pqr py<3.13; cat=A\n",
+		},
+		{
+			name: "op form to name",
+			src:  "#pqr py>=3.11; cat=A\n",
+			key:  "py", value: "ds311",
+			want: "#pqr py=ds311; cat=A\n",
+		},
+		{
+			name: "alias key updates existing entry",
+			src:  "#pqr ubuntu=/usr/bin/python3\n",
+			key:  "linux", value: "/opt/py/bin/python",
+			want: "#pqr ubuntu=/opt/py/bin/python\n",
+		},
+		{
+			name: "env rewrites the same variable",
+			src:  "#pqr env=A=1; env=B=2\n",
+			key:  "env", value: "A=3",
+			want: "#pqr env=A=3; env=B=2\n",
+		},
+		{
+			name: "env adds a new variable",
+			src:  "#pqr env=A=1\n",
+			key:  "env", value: "B=2",
+			want: "#pqr env=A=1; env=B=2\n",
+		},
+		{
+			name: "empty value deletes",
+			src:  "#pqr cat=A; term=true\n",
+			key:  "cat", value: "",
+			want: "#pqr term=true\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDocument(tt.src)
+			d.Set(tt.key, tt.value)
+			if got := d.String(); got != tt.want {
+				t.Errorf("Set(%q, %q):\n got %q\nwant %q", tt.key, tt.value, got, tt.want)
+			}
+			h, err := d.Header()
+			if err != nil {
+				t.Fatalf("Header: %v", err)
+			}
+			if tt.key == "env" {
+				if !slices.Contains(h.Env, tt.value) {
+					t.Errorf("Env after Set = %q, want it to contain %q", h.Env, tt.value)
+				}
+			} else if got, _ := h.Get(tt.key); got != tt.value {
+				t.Errorf("Get(%q) after Set = %q, want %q", tt.key, got, tt.value)
+			}
+		})
+	}
+}
+
+func TestDocumentDelete(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		key  string
+		want string
+	}{
+		{
+			name: "first entry",
+			src:  "#pqr cat=A; term=true\n",
+			key:  "cat",
+			want: "#pqr term=true\n",
+		},
+		{
+			name: "last entry",
+			src:  "#pqr cat=A; term=true\n",
+			key:  "term",
+			want: "#pqr cat=A\n",
+		},
+		{
+			name: "only entry removes the line",
+			src:  "#pqr cat=A\r\nprint(1)\r\n",
+			key:  "cat",
+			want: "print(1)\r\n",
+		},
+		{
+			name: "block line",
+			src:  "#pqr\n# cat=A\n# term=true\n#/pqr\n",
+			key:  "cat",
+			want: "#pqr\n# term=true\n#/pqr\n",
+		},
+		{
+			name: "block line next to a comment",
+			src:  "#pqr\n# Nightly report\n# cat=A\n#/pqr\n",
+			key:  "cat",
+			want: "#pqr\n# Nightly report\n#/pqr\n",
+		},
+		{
+			name: "every occurrence",
+			src:  "#pqr cat=A\n#pqr cat=B; term=true\n",
+			key:  "cat",
+			want: "#pqr term=true\n",
+		},
+		{
+			name: "legacy line",
+			src:  BOM + "#pqr cat \"A\"\n#pqr terminal true\n",
+			key:  "term",
+			want: BOM + "#pqr cat \"A\"\n",
+		},
+		{
+			name: "op form",
+			src:  "#pqr py>=3.11; cat=A\n",
+			key:  "py",
+			want: "#pqr cat=A\n",
+		},
+		{
+			name: "quoted value with semicolon",
+			src:  `#pqr win="C:\a;b\python.exe"; cat=A` + "\n",
+			key:  "win",
+			want: "#pqr cat=A\n",
+		},
+		{
+			name: "missing key",
+			src:  "#pqr cat=A\n",
+			key:  "term",
+			want: "#pqr cat=A\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDocument(tt.src)
+			d.Delete(tt.key)
+			if got := d.String(); got != tt.want {
+				t.Errorf("Delete(%q):\n got %q\nwant %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
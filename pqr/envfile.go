@@ -0,0 +1,114 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package pqr
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ReadEnvFile reads a dotenv file: KEY=VALUE lines with an optional "export "
+// prefix, blank lines and # comments. Values may be single-quoted (literal)
+// or double-quoted (\n \r \t \" \\ escapes); an unquoted value ends at " #".
+// Variables are not expanded.
+func ReadEnvFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var env []string
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if n == 1 {
+			line = strings.TrimPrefix(line, BOM)
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, val, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !IsEnvName(key) {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, n)
+		}
+		val = strings.TrimSpace(val)
+		if val != "" && (val[0] == '"' || val[0] == '\'') {
+			v, ok := unquoteEnv(val)
+			if !ok {
+				return nil, fmt.Errorf("%s:%d: unterminated quoted value", path, n)
+			}
+			val = v
+		} else if i := strings.Index(val, " #"); i >= 0 {
+			val = strings.TrimSpace(val[:i])
+		}
+		env = append(env, key+"="+val)
+	}
+	return env, scanner.Err()
+}
+
+// unquoteEnv decodes a quoted dotenv value. Unlike #pqr values, double
+// quotes understand \n, \r and \t as well, as other dotenv readers do; an
+// unknown escape is kept as written. Text after the closing quote is ignored.
+func unquoteEnv(s string) (string, bool) {
+	q := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if c == q {
+			return b.String(), true
+		}
+		if c == '\\' && q == '"' && i+1 < len(s) {
+			switch s[i+1] {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case '"', '\\':
+				c = s[i+1]
+			default:
+				b.WriteByte(c)
+				continue
+			}
+			i++
+		}
+		b.WriteByte(c)
+	}
+	return "", false
+}
+
+// IsEnvName reports whether s is a valid environment variable name
+// ([A-Za-z_][A-Za-z0-9_]*).
+func IsEnvName(s string) bool {
+	if s == "" || s[0] >= '0' && s[0] <= '9' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// Environ returns the variables the header adds to the script's
+// environment: the envfile= entries first, then env= so that the header wins.
+// A relative envfile= is resolved against scriptDir.
+func (h Header) Environ(scriptDir string) ([]string, error) {
+	var env []string
+	if h.EnvFile != "" {
+		fileEnv, err := ReadEnvFile(resolvePath(h.EnvFile, scriptDir))
+		if err != nil {
+			return nil, fmt.Errorf("envfile: %w", err)
+		}
+		env = append(env, fileEnv...)
+	}
+	return append(env, h.Env...), nil
+}
@@ -0,0 +1,71 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package pqr
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadEnvFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "plain",
+			src:  BOM + "# comment\n\nA=1\nexport B = two words \n",
+			want: []string{"A=1", "B=two words"},
+		},
+		{
+			name: "double-quoted escapes",
+			src:  `A="line\nnext\ttab\r \"q\" \\ \x"` + "\n",
+			want: []string{"A=line\nnext\ttab\r \"q\" \\ \\x"},
+		},
+		{
+			name: "single-quoted is literal",
+			src:  `A='line\nnext # not a comment'` + "\n",
+			want: []string{`A=line\nnext # not a comment`},
+		},
+		{
+			name: "unquoted comment and windows path",
+			src:  `A=C:\tools\new # path` + "\r\n",
+			want: []string{`A=C:\tools\new`},
+		},
+		{
+			name:    "unterminated quote",
+			src:     `A="open` + "\n",
+			wantErr: true,
+		},
+		{
+			name:    "bad name",
+			src:     "1A=x\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".env")
+			if err := os.WriteFile(path, []byte(tt.src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadEnvFile(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ReadEnvFile = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadEnvFile: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadEnvFile = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,446 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+// Package pqr reads and writes the "#pqr" header that PyQuickRun and
+// PyQuickBox use to store per-script launch settings inside .py files.
+package pqr
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header holds the settings found in a script's #pqr lines.
+type Header struct {
+	Found    bool     // at least one #pqr line was seen
+	Category string   // cat=
+	Def      string   // def= (interpreter for any OS)
+	Mac      string   // mac=
+	Win      string   // win=
+	Linux    string   // linux= (legacy: ubuntu)
+	Py       string   // py=, a name in the local interpreter registry or a version constraint (py>=3.11)
+	Runner   string   // runner=: python, uv, poetry, pipenv, hatch, conda:<env> or a template
+	Conda    string   // conda=, conda environment name or prefix
+	Term     *bool    // term=, nil when not set
+	Args     string   // args=, script arguments (shell-style)
+	PyFlags  string   // pyflags=, interpreter flags placed before the script
+	Env      []string // env=KEY=VALUE, one entry per variable
+	EnvFile  string   // envfile=, dotenv file relative to the script
+	Cwd      string   // cwd=: script, project or a directory
+	Timeout  string   // timeout=, e.g. 90s or 10m; "0" or "none" for no limit
+	Instance string   // instance=: single, queue or parallel (the default)
+	Stdin    string   // stdin=file:<path>, a file fed to the script's stdin
+	Extra    []Entry  // keys this package does not know, in source order
+
+	Script *ScriptMetadata // PEP 723 "# /// script" block, nil without one
+}
+
+// Values of instance=, which says what happens when a script is started
+// again while a run of it is still going.
+const (
+	InstanceParallel = "parallel" // start another copy
+	InstanceSingle   = "single"   // refuse, showing the existing run
+	InstanceQueue    = "queue"    // start once the current run has exited
+)
+
+// Interpreter returns the interpreter configured for goos ("darwin",
+// "windows", "linux"), falling back to def=. It returns "" when neither is set.
+func (h Header) Interpreter(goos string) string {
+	var v string
+	switch goos {
+	case "darwin":
+		v = h.Mac
+	case "windows":
+		v = h.Win
+	case "linux":
+		v = h.Linux
+	}
+	if v == "" {
+		v = h.Def
+	}
+	return v
+}
+
+// IsVersionConstraint reports whether a py= value is a version constraint
+// such as ">=3.11" or "3.12" rather than an interpreter name.
+func IsVersionConstraint(v string) bool {
+	return v != "" && strings.IndexByte("<>=!~0123456789", v[0]) >= 0
+}
+
+// IsInterpreterName reports whether s can name an interpreter for py=: it
+// starts with a letter, as keys do, and has no spaces, quotes or ";=<>!~".
+func IsInterpreterName(s string) bool {
+	return s != "" && isKeyStart(s[0]) && !strings.ContainsAny(s, " \t;=\"'<>!~")
+}
+
+// PyAlias returns py= when it names an interpreter.
+func (h Header) PyAlias() string {
+	if IsVersionConstraint(h.Py) {
+		return ""
+	}
+	return h.Py
+}
+
+// PythonConstraint returns the version constraint the script declares:
+// py= when it is one, else PEP 723 requires-python.
+func (h Header) PythonConstraint() string {
+	if IsVersionConstraint(h.Py) {
+		return h.Py
+	}
+	if h.Script != nil {
+		return h.Script.RequiresPython
+	}
+	return ""
+}
+
+// Terminal reports whether term= asks for a terminal window.
+func (h Header) Terminal() bool {
+	return h.Term != nil && *h.Term
+}
+
+// InstancePolicy returns instance=, or InstanceParallel when it is not set.
+func (h Header) InstancePolicy() string {
+	if h.Instance == "" {
+		return InstanceParallel
+	}
+	return h.Instance
+}
+
+// StdinFile returns the file named by stdin=file:<path>, resolved against
+// the script's directory, or "" when stdin= is not set.
+func (h Header) StdinFile(scriptPath string) (string, error) {
+	if h.Stdin == "" {
+		return "", nil
+	}
+	path := resolvePath(strings.TrimPrefix(h.Stdin, "file:"), filepath.Dir(scriptPath))
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("stdin: %w", err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("stdin: %s is a directory", path)
+	}
+	return path, nil
+}
+
+// ScriptArgs splits args= into the arguments passed after the script path.
+func (h Header) ScriptArgs() ([]string, error) {
+	return SplitArgs(h.Args)
+}
+
+// InterpreterFlags splits pyflags= into the flags passed before the script
+// path, e.g. "-X dev -O".
+func (h Header) InterpreterFlags() ([]string, error) {
+	return SplitArgs(h.PyFlags)
+}
+
+// ParseTimeout reads a timeout= value: a duration such as "90s", "10m" or
+// "1h30m", or a number of seconds. "0", "none" and "off" mean no limit.
+func ParseTimeout(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "", "0", "none", "off":
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		n, nerr := strconv.ParseFloat(s, 64)
+		if nerr != nil {
+			return 0, fmt.Errorf("timeout: invalid duration %q (want e.g. 90s, 10m)", s)
+		}
+		d = time.Duration(n * float64(time.Second))
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("timeout: negative duration %q", s)
+	}
+	return d, nil
+}
+
+// Get returns the value of key, known or not. For env= it is the last entry.
+func (h Header) Get(key string) (string, bool) {
+	key = canonicalKey(key)
+	switch key {
+	case "cat":
+		return h.Category, h.Category != ""
+	case "def":
+		return h.Def, h.Def != ""
+	case "mac":
+		return h.Mac, h.Mac != ""
+	case "win":
+		return h.Win, h.Win != ""
+	case "linux":
+		return h.Linux, h.Linux != ""
+	case "py":
+		return h.Py, h.Py != ""
+	case "runner":
+		return h.Runner, h.Runner != ""
+	case "conda":
+		return h.Conda, h.Conda != ""
+	case "term":
+		if h.Term == nil {
+			return "", false
+		}
+		return strconv.FormatBool(*h.Term), true
+	case "args":
+		return h.Args, h.Args != ""
+	case "pyflags":
+		return h.PyFlags, h.PyFlags != ""
+	case "env":
+		if len(h.Env) == 0 {
+			return "", false
+		}
+		return h.Env[len(h.Env)-1], true
+	case "envfile":
+		return h.EnvFile, h.EnvFile != ""
+	case "cwd":
+		return h.Cwd, h.Cwd != ""
+	case "timeout":
+		return h.Timeout, h.Timeout != ""
+	case "instance":
+		return h.Instance, h.Instance != ""
+	case "stdin":
+		return h.Stdin, h.Stdin != ""
+	}
+	for _, e := range h.Extra {
+		if e.Key == key {
+			return e.Value, true
+		}
+	}
+	return "", false
+}
+
+// ParseFile reads the header of the script at path. Syntax problems are
+// reported as an ErrorList alongside the usable part of the header.
+func ParseFile(path string) (Header, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Header{}, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse scans every line of r and merges all #pqr lines and blocks into one
+// Header. Later lines override earlier ones. A PEP 723 "# /// script" block
+// is read into Header.Script.
+func Parse(r io.Reader) (Header, error) {
+	var h Header
+	var errs ErrorList
+	blockLine := 0 // line of the open block's "#pqr", 0 outside a block
+	var meta *metadataBlock
+	closeMeta := func() {
+		m, err := meta.decode()
+		switch {
+		case err != nil:
+			errs = append(errs, err)
+		case h.Script != nil:
+			errs = append(errs, &SyntaxError{Line: meta.line, Column: 1, Msg: "duplicate " + MetadataStart + " block"})
+		default:
+			h.Script = m
+		}
+		meta = nil
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if n == 1 {
+			line = strings.TrimPrefix(line, BOM)
+		}
+		if trimmed := strings.TrimSuffix(line, "\r"); meta != nil {
+			if isMetadataLine(trimmed) {
+				meta.lines = append(meta.lines, trimmed)
+				continue
+			}
+			closeMeta()
+		} else if blockLine == 0 && trimmed == MetadataStart {
+			meta = &metadataBlock{line: n}
+			continue
+		}
+		var lineErrs ErrorList
+		switch kind := classify(line, blockLine != 0); kind {
+		case lineBlockStart:
+			h.Found = true
+			blockLine = n
+		case lineBlockEnd:
+			blockLine = 0
+		case lineBlockBody:
+			lineErrs = h.parseBody(line)
+		case lineBlockComment:
+			// a plain comment inside the block
+		default:
+			if blockLine != 0 {
+				errs = append(errs, &SyntaxError{Line: blockLine, Column: 1, Msg: "unterminated #pqr block (missing " + BlockEnd + ")"})
+				blockLine = 0
+			}
+			if kind == lineSingle {
+				lineErrs = h.parseLine(line)
+			}
+		}
+		for _, e := range lineErrs {
+			e.Line = n
+			errs = append(errs, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return h, err
+	}
+	if meta != nil {
+		closeMeta()
+	}
+	if blockLine != 0 {
+		errs = append(errs, &SyntaxError{Line: blockLine, Column: 1, Msg: "unterminated #pqr block (missing " + BlockEnd + ")"})
+	}
+	return h, errs.Err()
+}
+
+var legacyRe = regexp.MustCompile(`^(cat|def|mac|win|linux|ubuntu)\s+"([^"]*)"\s*$`)
+
+// parseLine applies a single "#pqr key=val; ..." line to h. Errors carry only
+// the column.
+func (h *Header) parseLine(line string) ErrorList {
+	trimmed := strings.TrimLeft(line, " \t")
+	h.Found = true
+	col := len(line) - len(trimmed) + 5
+	content := strings.TrimRight(trimmed[4:], " \t\r")
+
+	if e, ok := legacyEntry(content); ok {
+		return h.apply([]scannedEntry{{Entry: e, col: col}})
+	}
+	entries, errs := scanEntries(content, col)
+	return append(errs, h.apply(entries)...)
+}
+
+// legacyEntry recognises the old `#pqr key "val"` and `#pqr terminal true`
+// forms in the text after "#pqr".
+func legacyEntry(content string) (Entry, bool) {
+	content = strings.TrimSpace(content)
+	if m := legacyRe.FindStringSubmatch(content); m != nil {
+		return Entry{Key: m[1], Value: m[2]}, true
+	}
+	if fields := strings.Fields(content); len(fields) == 2 && fields[0] == "terminal" {
+		return Entry{Key: "terminal", Value: fields[1]}, true
+	}
+	return Entry{}, false
+}
+
+// canonicalKey maps key aliases to the name Document.Set writes.
+func canonicalKey(key string) string {
+	switch key = strings.ToLower(key); key {
+	case "ubuntu":
+		return "linux"
+	case "terminal":
+		return "term"
+	}
+	return key
+}
+
+// parseBody applies a "# key=val; ..." line inside a block to h.
+func (h *Header) parseBody(line string) ErrorList {
+	trimmed := strings.TrimLeft(line, " \t")
+	col := len(line) - len(trimmed) + 2
+	entries, errs := scanEntries(strings.TrimRight(trimmed[1:], " \t\r"), col)
+	return append(errs, h.apply(entries)...)
+}
+
+func (h *Header) apply(entries []scannedEntry) ErrorList {
+	var errs ErrorList
+	for _, e := range entries {
+		if err := h.Set(e.Key, e.Value); err != nil {
+			errs = append(errs, &SyntaxError{Column: e.col, Msg: err.Error()})
+		}
+	}
+	return errs
+}
+
+// Set stores value under key. Unknown keys go to Extra.
+func (h *Header) Set(key, value string) error {
+	key = canonicalKey(key)
+	switch key {
+	case "cat":
+		h.Category = value
+	case "def":
+		h.Def = value
+	case "mac":
+		h.Mac = value
+	case "win":
+		h.Win = value
+	case "linux":
+		h.Linux = value
+	case "py":
+		h.Py = value
+	case "runner":
+		h.Runner = value
+	case "conda":
+		h.Conda = value
+	case "term":
+		b, ok := parseBool(value)
+		if !ok {
+			return fmt.Errorf("invalid boolean %q for %s", value, key)
+		}
+		h.Term = &b
+	case "args", "pyflags":
+		if _, err := SplitArgs(value); err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+		if key == "args" {
+			h.Args = value
+		} else {
+			h.PyFlags = value
+		}
+	case "env":
+		// env= may repeat; a later entry for the same variable wins.
+		name, val, ok := strings.Cut(value, "=")
+		name = strings.TrimSpace(name)
+		if !ok || !IsEnvName(name) {
+			return fmt.Errorf("env: expected KEY=VALUE, got %q", value)
+		}
+		h.Env = append(h.Env, name+"="+val)
+	case "envfile":
+		h.EnvFile = value
+	case "cwd":
+		h.Cwd = value
+	case "timeout":
+		if _, err := ParseTimeout(value); err != nil {
+			return err
+		}
+		h.Timeout = value
+	case "instance":
+		switch v := strings.ToLower(value); v {
+		case InstanceParallel, InstanceSingle, InstanceQueue:
+			h.Instance = v
+		default:
+			return fmt.Errorf("instance: expected single, queue or parallel, got %q", value)
+		}
+	case "stdin":
+		if path, ok := strings.CutPrefix(value, "file:"); !ok || path == "" {
+			return fmt.Errorf("stdin: expected file:<path>, got %q", value)
+		}
+		h.Stdin = value
+	default:
+		for i := range h.Extra {
+			if h.Extra[i].Key == key {
+				h.Extra[i].Value = value
+				return nil
+			}
+		}
+		h.Extra = append(h.Extra, Entry{Key: key, Value: value})
+	}
+	return nil
+}
+
+func parseBool(s string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "1", "yes", "on":
+		return true, true
+	case "false", "0", "no", "off", "":
+		return false, true
+	}
+	return false, false
+}
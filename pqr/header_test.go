@@ -0,0 +1,54 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package pqr
+
+import "testing"
+
+func TestPythonConstraint(t *testing.T) {
+	meta := &ScriptMetadata{RequiresPython: ">=3.10"}
+	tests := []struct {
+		h         Header
+		want      string
+		wantAlias string
+	}{
+		{Header{}, "", ""},
+		{Header{Script: meta}, ">=3.10", ""},
+		{Header{Py: ">=3.12", Script: meta}, ">=3.12", ""},
+		{Header{Py: "3.11"}, "3.11", ""},
+		{Header{Py: "~=3.11"}, "~=3.11", ""},
+		{Header{Py: "ds311", Script: meta}, ">=3.10", "ds311"},
+	}
+	for _, tt := range tests {
+		if got := tt.h.PythonConstraint(); got != tt.want {
+			t.Errorf("PythonConstraint(py=%q) = %q, want %q", tt.h.Py, got, tt.want)
+		}
+		if got := tt.h.PyAlias(); got != tt.wantAlias {
+			t.Errorf("PyAlias(py=%q) = %q, want %q", tt.h.Py, got, tt.wantAlias)
+		}
+	}
+}
+
+func TestIsInterpreterName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"ds311", true},
+		{"py3.12-venv", true},
+		{"Work_env", true},
+		{"", false},
+		{"311", false},
+		{"_env", false},
+		{"-x", false},
+		{"my env", false},
+		{"a=b", false},
+		{"a;b", false},
+		{`"q"`, false},
+		{"a>3", false},
+	}
+	for _, tt := range tests {
+		if got := IsInterpreterName(tt.name); got != tt.want {
+			t.Errorf("IsInterpreterName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
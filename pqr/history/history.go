@@ -0,0 +1,213 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+// Package history keeps a record of script runs on disk: what ran, with
+// which interpreter, when, how it ended, and the tail of its output, for the
+// history views of PyQuickRun and PyQuickBox.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Record is one run.
+type Record struct {
+	ID          string    `json:"id"`
+	Script      string    `json:"script"`
+	Interpreter string    `json:"interpreter"` // e.g. "/usr/bin/python3 (Default)"
+	Argv        []string  `json:"argv"`        // full command line
+	Args        []string  `json:"args"`        // script arguments
+	Dir         string    `json:"cwd"`
+	Terminal    bool      `json:"terminal"` // ran in a terminal window; no output captured
+	Started     time.Time `json:"started"`
+	Ended       time.Time `json:"ended"`
+	ExitCode    int       `json:"exit_code"`           // -1 when killed by a signal
+	Truncated   bool      `json:"truncated"`           // output was longer than MaxOutput
+	TimedOut    bool      `json:"timed_out,omitempty"` // stopped after reaching its timeout
+}
+
+// Duration returns how long the run took.
+func (r *Record) Duration() time.Duration { return r.Ended.Sub(r.Started) }
+
+// Chunk is a piece of captured output.
+type Chunk struct {
+	Stderr bool   `json:"stderr,omitempty"`
+	Text   string `json:"text"`
+}
+
+// MaxOutput is how many bytes of output a run keeps; older output is
+// dropped first.
+const MaxOutput = 256 * 1024
+
+// Capture collects the output of a run as it is written, keeping the last
+// MaxOutput bytes. It is safe for concurrent use.
+type Capture struct {
+	mu        sync.Mutex
+	chunks    []Chunk
+	size      int
+	truncated bool
+}
+
+// Write adds output from stdout or, with stderr set, from stderr.
+func (c *Capture) Write(stderr bool, p []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if n := len(c.chunks); n > 0 && c.chunks[n-1].Stderr == stderr {
+		c.chunks[n-1].Text += string(p)
+	} else {
+		c.chunks = append(c.chunks, Chunk{Stderr: stderr, Text: string(p)})
+	}
+	c.size += len(p)
+	for c.size > MaxOutput {
+		c.truncated = true
+		over := c.size - MaxOutput
+		if first := c.chunks[0].Text; len(first) > over {
+			for over < len(first) && !utf8.RuneStart(first[over]) {
+				over++ // don't cut a character in half
+			}
+			c.chunks[0].Text = first[over:]
+			c.size -= over
+		} else {
+			c.chunks = c.chunks[1:]
+			c.size -= len(first)
+		}
+	}
+}
+
+// Chunks returns the captured output and whether any was dropped.
+func (c *Capture) Chunks() ([]Chunk, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Chunk(nil), c.chunks...), c.truncated
+}
+
+// DefaultLimit is how many runs a Store keeps unless told otherwise.
+const DefaultLimit = 200
+
+// Store keeps runs in a directory: <id>.json for the record and
+// <id>.out.json for the output.
+type Store struct {
+	Dir   string
+	Limit int // runs kept; older ones are deleted by Add
+}
+
+// Open returns the store in dir, which is created on the first Add.
+func Open(dir string) *Store {
+	return &Store{Dir: dir, Limit: DefaultLimit}
+}
+
+// Add saves r and its output, assigning r.ID, and deletes the oldest runs
+// beyond s.Limit.
+func (s *Store) Add(r *Record, output []Chunk) error {
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	r.ID = strconv.FormatInt(r.Started.UnixNano(), 10)
+	if err := writeJSON(filepath.Join(s.Dir, r.ID+".json"), r); err != nil {
+		return err
+	}
+	if len(output) > 0 {
+		if err := writeJSON(filepath.Join(s.Dir, r.ID+".out.json"), output); err != nil {
+			return err
+		}
+	}
+	return s.prune()
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// ids returns the IDs of the stored runs, newest first.
+func (s *Store) ids() ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasSuffix(name, ".json") && !strings.HasSuffix(name, ".out.json") {
+			ids = append(ids, strings.TrimSuffix(name, ".json"))
+		}
+	}
+	// IDs are nanosecond timestamps: compare by length, then text
+	sort.Slice(ids, func(i, j int) bool {
+		if len(ids[i]) != len(ids[j]) {
+			return len(ids[i]) > len(ids[j])
+		}
+		return ids[i] > ids[j]
+	})
+	return ids, nil
+}
+
+func (s *Store) prune() error {
+	ids, err := s.ids()
+	if err != nil || s.Limit <= 0 || len(ids) <= s.Limit {
+		return err
+	}
+	for _, id := range ids[s.Limit:] {
+		_ = s.Delete(id)
+	}
+	return nil
+}
+
+// List returns up to n runs, newest first; n <= 0 means all. Unreadable
+// records are skipped.
+func (s *Store) List(n int) ([]*Record, error) {
+	ids, err := s.ids()
+	if err != nil {
+		return nil, err
+	}
+	var records []*Record
+	for _, id := range ids {
+		if n > 0 && len(records) == n {
+			break
+		}
+		data, err := os.ReadFile(filepath.Join(s.Dir, id+".json"))
+		if err != nil {
+			continue
+		}
+		r := &Record{}
+		if json.Unmarshal(data, r) == nil {
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
+
+// Output returns the captured output of run id, or nothing for runs
+// without any.
+func (s *Store) Output(id string) ([]Chunk, error) {
+	data, err := os.ReadFile(filepath.Join(s.Dir, id+".out.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var output []Chunk
+	err = json.Unmarshal(data, &output)
+	return output, err
+}
+
+// Delete removes run id.
+func (s *Store) Delete(id string) error {
+	_ = os.Remove(filepath.Join(s.Dir, id+".out.json"))
+	return os.Remove(filepath.Join(s.Dir, id+".json"))
+}
@@ -0,0 +1,232 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package launch
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CondaEnv is one conda environment.
+type CondaEnv struct {
+	Name   string // "base" for the root environment, else the directory name
+	Prefix string // environment directory
+}
+
+// Python returns the interpreter of the environment.
+func (e CondaEnv) Python() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(e.Prefix, "python.exe")
+	}
+	return filepath.Join(e.Prefix, "bin", "python")
+}
+
+// CondaTools are asked for their environments, in order.
+var CondaTools = []string{"conda", "mamba", "micromamba"}
+
+// EnvironmentFiles are the conda environment files looked for next to a
+// script and in its parents.
+var EnvironmentFiles = []string{"environment.yml", "environment.yaml"}
+
+var condaCache struct {
+	sync.Mutex
+	envs []CondaEnv
+	at   time.Time
+}
+
+// CondaEnvs lists the conda environments known on this machine, asking the
+// first of CondaTools that answers "env list --json" and falling back to
+// ~/.conda/environments.txt. The list is cached for a minute.
+func CondaEnvs() []CondaEnv {
+	condaCache.Lock()
+	defer condaCache.Unlock()
+	if condaCache.envs != nil && time.Since(condaCache.at) < time.Minute {
+		return condaCache.envs
+	}
+	prefixes := condaToolPrefixes()
+	if prefixes == nil {
+		prefixes = condaFilePrefixes()
+	}
+	envs := []CondaEnv{}
+	seen := map[string]bool{}
+	for _, p := range prefixes {
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		if info, err := os.Stat(filepath.Join(p, "conda-meta")); err != nil || !info.IsDir() {
+			continue
+		}
+		envs = append(envs, CondaEnv{Name: condaEnvName(p), Prefix: p})
+	}
+	condaCache.envs, condaCache.at = envs, time.Now()
+	return envs
+}
+
+func condaToolPrefixes() []string {
+	tools := CondaTools
+	if exe := os.Getenv("CONDA_EXE"); exe != "" {
+		tools = append([]string{exe}, tools...)
+	}
+	for _, tool := range tools {
+		path, err := FindProgram(tool)
+		if err != nil {
+			continue
+		}
+		out, err := exec.Command(path, "env", "list", "--json").Output()
+		if err != nil {
+			continue
+		}
+		var list struct {
+			Envs []string `json:"envs"`
+		}
+		if json.Unmarshal(out, &list) == nil {
+			return list.Envs
+		}
+	}
+	return nil
+}
+
+func condaFilePrefixes() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	f, err := os.Open(filepath.Join(home, ".conda", "environments.txt"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var prefixes []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if p := strings.TrimSpace(scanner.Text()); p != "" {
+			prefixes = append(prefixes, p)
+		}
+	}
+	return prefixes
+}
+
+// condaEnvName names the environment at prefix. Named environments live in
+// an "envs" directory; anything else is a root install.
+func condaEnvName(prefix string) string {
+	if filepath.Base(filepath.Dir(prefix)) == "envs" {
+		return filepath.Base(prefix)
+	}
+	return "base"
+}
+
+// FindCondaEnv returns the environment called name, or the one at name when
+// it is a path.
+func FindCondaEnv(name string) (CondaEnv, error) {
+	if strings.ContainsAny(name, `/\`) {
+		prefix, err := filepath.Abs(name)
+		if err != nil {
+			return CondaEnv{}, err
+		}
+		if _, err := os.Stat(filepath.Join(prefix, "conda-meta")); err != nil {
+			return CondaEnv{}, fmt.Errorf("conda: %s is not a conda environment", prefix)
+		}
+		return CondaEnv{Name: condaEnvName(prefix), Prefix: prefix}, nil
+	}
+	for _, e := range CondaEnvs() {
+		if e.Name == name {
+			return e, nil
+		}
+	}
+	return CondaEnv{}, fmt.Errorf("conda: environment %q not found", name)
+}
+
+// FindEnvironmentFile walks up from dir for at most VenvSearchDepth
+// directories and returns the environment name declared by the first
+// EnvironmentFiles entry with a "name:" line.
+func FindEnvironmentFile(dir string) string {
+	for i := 0; i < VenvSearchDepth; i++ {
+		for _, file := range EnvironmentFiles {
+			if name := environmentName(filepath.Join(dir, file)); name != "" {
+				return name
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return ""
+}
+
+// environmentName reads the top-level "name:" of an environment file.
+func environmentName(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if v, ok := strings.CutPrefix(scanner.Text(), "name:"); ok {
+			v, _, _ = strings.Cut(v, "#")
+			return strings.Trim(strings.TrimSpace(v), `"'`)
+		}
+	}
+	return ""
+}
+
+// CondaOf returns the conda environment python belongs to, found through
+// the conda-meta directory of its prefix.
+func CondaOf(python string) (CondaEnv, bool) {
+	if !strings.ContainsAny(python, `/\`) {
+		return CondaEnv{}, false
+	}
+	abs, err := filepath.Abs(python)
+	if err != nil {
+		return CondaEnv{}, false
+	}
+	// <prefix>/bin/python, or <prefix>\python.exe on Windows
+	for _, prefix := range []string{filepath.Dir(filepath.Dir(abs)), filepath.Dir(abs)} {
+		if info, err := os.Stat(filepath.Join(prefix, "conda-meta")); err == nil && info.IsDir() {
+			return CondaEnv{Name: condaEnvName(prefix), Prefix: prefix}, true
+		}
+	}
+	return CondaEnv{}, false
+}
+
+// Environ returns the variables "conda activate" would set for e.
+func (e CondaEnv) Environ() []string {
+	dirs := []string{filepath.Join(e.Prefix, "bin")}
+	if runtime.GOOS == "windows" {
+		dirs = []string{
+			e.Prefix,
+			filepath.Join(e.Prefix, "Library", "mingw-w64", "bin"),
+			filepath.Join(e.Prefix, "Library", "usr", "bin"),
+			filepath.Join(e.Prefix, "Library", "bin"),
+			filepath.Join(e.Prefix, "Scripts"),
+		}
+	}
+	sep := string(os.PathListSeparator)
+	return []string{
+		"CONDA_PREFIX=" + e.Prefix,
+		"CONDA_DEFAULT_ENV=" + e.Name,
+		"CONDA_SHLVL=1",
+		"PATH=" + strings.Join(dirs, sep) + sep + os.Getenv("PATH"),
+	}
+}
+
+// InterpreterChoices returns interpreters for the pickers in the settings
+// UIs: the python of every known conda environment.
+func InterpreterChoices() []string {
+	var choices []string
+	for _, e := range CondaEnvs() {
+		choices = append(choices, e.Python())
+	}
+	return choices
+}
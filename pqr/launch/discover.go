@@ -0,0 +1,103 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package launch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sync"
+)
+
+// Discovered is a working interpreter found by Discover.
+type Discovered struct {
+	Python string
+	Source string // "recent venv", "PATH", "pyenv", "asdf", "uv", "conda(<name>)"
+	Info   PythonInfo
+}
+
+// String describes d for pickers, e.g.
+// "Python 3.11.7 (CPython) · pyenv · /home/me/.pyenv/versions/3.11.7/bin/python".
+func (d Discovered) String() string {
+	return fmt.Sprintf("%s · %s · %s", d.Info, d.Source, d.Python)
+}
+
+// pythonName matches the interpreter names Discover looks for on PATH.
+var pythonName = regexp.MustCompile(`^python(3(\.[0-9]+)?)?(\.exe)?$`)
+
+// Discover lists the interpreters on this machine that answer Probe: recent
+// (interpreters of recently used venvs), python3* on PATH and in /usr/bin,
+// pyenv, asdf and uv-managed installs, then conda environments. An
+// interpreter found twice is listed once, under its first source.
+// Interpreters are probed in parallel.
+func Discover(recent []string) []Discovered {
+	var found []Discovered
+	add := func(python, source string) {
+		found = append(found, Discovered{Python: python, Source: source})
+	}
+	for _, python := range recent {
+		add(python, "recent venv")
+	}
+	dirs := filepath.SplitList(os.Getenv("PATH"))
+	if runtime.GOOS != "windows" {
+		dirs = append(dirs, "/usr/bin")
+	}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if pythonName.MatchString(e.Name()) {
+				add(filepath.Join(dir, e.Name()), "PATH")
+			}
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		for _, m := range versionManagers {
+			for _, python := range m.all(m.dir(home)) {
+				add(python, m.name)
+			}
+		}
+	}
+	for _, env := range CondaEnvs() {
+		add(env.Python(), "conda("+env.Name+")")
+	}
+
+	// Compare by directory with symlinks resolved (/bin is often /usr/bin),
+	// but not the interpreter itself: a venv python links to its base.
+	seen := map[string]bool{}
+	var unique []Discovered
+	for _, d := range found {
+		key := d.Python
+		if dir, err := filepath.EvalSymlinks(filepath.Dir(d.Python)); err == nil {
+			key = filepath.Join(dir, filepath.Base(d.Python))
+		}
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, d)
+		}
+	}
+
+	var wg sync.WaitGroup
+	ok := make([]bool, len(unique))
+	for i := range unique {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			info, err := Probe(unique[i].Python)
+			unique[i].Info, ok[i] = info, err == nil
+		}(i)
+	}
+	wg.Wait()
+
+	var working []Discovered
+	for i, d := range unique {
+		if ok[i] {
+			working = append(working, d)
+		}
+	}
+	return working
+}
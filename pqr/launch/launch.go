@@ -0,0 +1,211 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+// Package launch turns a resolved script run into the command that starts
+// it, either directly or inside a new terminal window, so that PyQuickRun and
+// PyQuickBox start scripts the same way.
+package launch
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"pqr"
+)
+
+// Spec describes one script run.
+type Spec struct {
+	Argv []string // program and arguments, e.g. python, flags, script, args
+	Dir  string   // working directory, "" for the launcher's own
+	Env  []string // KEY=VALUE pairs added to or replacing os.Environ()
+
+	// Timeout stops a run started with Start once it has run this long; 0
+	// means no limit. Terminal runs are not timed.
+	Timeout time.Duration
+
+	// Stdin is a file fed to the standard input of a run started with Start.
+	// Without one the run gets a pipe, see Run.Stdin.
+	Stdin string
+}
+
+// DefaultMetadataRunner runs scripts that carry PEP 723 metadata, so their
+// dependencies are installed before the script starts.
+const DefaultMetadataRunner = "uv run --script"
+
+// FindProgram resolves name through PATH. Apps started from a desktop often
+// get a short PATH, so the usual per-user install directories (~/.local/bin,
+// ~/.cargo/bin) are tried as well.
+func FindProgram(name string) (string, error) {
+	path, err := exec.LookPath(name)
+	if err == nil || strings.ContainsAny(name, `/\`) {
+		return path, err
+	}
+	if home, herr := os.UserHomeDir(); herr == nil {
+		for _, dir := range []string{".local/bin", ".cargo/bin"} {
+			if p, perr := exec.LookPath(filepath.Join(home, dir, name)); perr == nil {
+				return p, nil
+			}
+		}
+	}
+	return "", err
+}
+
+// Environ returns os.Environ() with s.Env applied on top.
+func (s Spec) Environ() []string {
+	return MergeEnv(os.Environ(), s.Env)
+}
+
+// MergeEnv returns base with each KEY=VALUE of extra added, replacing an
+// existing entry for the same key. Keys compare case-insensitively on
+// Windows.
+func MergeEnv(base, extra []string) []string {
+	env := append([]string(nil), base...)
+	for _, kv := range extra {
+		key, _, _ := strings.Cut(kv, "=")
+		replaced := false
+		for i, old := range env {
+			oldKey, _, _ := strings.Cut(old, "=")
+			if oldKey == key || runtime.GOOS == "windows" && strings.EqualFold(oldKey, key) {
+				env[i] = kv
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			env = append(env, kv)
+		}
+	}
+	return env
+}
+
+// Command returns the command that runs s directly.
+func (s Spec) Command() *exec.Cmd {
+	cmd := exec.Command(s.Argv[0], s.Argv[1:]...)
+	cmd.Dir = s.Dir
+	cmd.Env = s.Environ()
+	return cmd
+}
+
+// ShellScript renders s as a POSIX shell script: cd, exports, the quoted
+// argv, and a final report of the exit code. With pause set the script waits
+// for Enter before returning.
+func (s Spec) ShellScript(pause bool) (string, error) {
+	var b strings.Builder
+	if s.Dir != "" {
+		fmt.Fprintf(&b, "cd %s || exit 1\n", ShellQuote(s.Dir))
+	}
+	for _, kv := range s.Env {
+		key, val, _ := strings.Cut(kv, "=")
+		if !pqr.IsEnvName(key) {
+			return "", fmt.Errorf("invalid environment variable name %q", key)
+		}
+		fmt.Fprintf(&b, "export %s=%s\n", key, ShellQuote(val))
+	}
+	quoted := make([]string, len(s.Argv))
+	for i, a := range s.Argv {
+		quoted[i] = ShellQuote(a)
+	}
+	b.WriteString(strings.Join(quoted, " ") + "\n")
+	b.WriteString("code=$?\necho\necho \"Exit Code: $code\"\n")
+	if pause {
+		b.WriteString("read -p 'Press Enter to exit...'\n")
+	}
+	return b.String(), nil
+}
+
+// writeScript stores script in a temporary file only the user can read.
+// The script removes itself as soon as the terminal starts it, so
+// variables from env files never show up on a command line.
+func writeScript(script string) (string, error) {
+	f, err := os.CreateTemp("", "pqr-run-*.sh")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.WriteString("rm -f -- \"$0\"\n" + script)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// linuxTerminals are tried in order; each prefix is followed by the path of
+// the script for bash.
+var linuxTerminals = [][]string{
+	{"ptyxis", "--", "bash"},
+	{"kgx", "--", "bash"},
+	{"gnome-terminal", "--", "bash"},
+	{"konsole", "-e", "bash"},
+	{"xfce4-terminal", "-x", "bash"},
+	{"xterm", "-e", "bash"},
+	{"x-terminal-emulator", "-e", "bash"},
+}
+
+// ErrNoTerminal is returned when no supported terminal emulator is installed.
+var ErrNoTerminal = errors.New("no supported terminal found")
+
+// TerminalCommand returns a command that opens a new terminal window running
+// s, together with the terminal's name. Terminals started through a server
+// process do not inherit the launcher's environment, so on macOS and Linux
+// the directory and variables go into a temporary script; on Windows the new
+// console inherits them from the returned command.
+func (s Spec) TerminalCommand() (*exec.Cmd, string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		args := append([]string{"/C", "start", "cmd", "/k"}, s.Argv...)
+		cmd := exec.Command("cmd", args...)
+		cmd.Dir = s.Dir
+		cmd.Env = s.Environ()
+		return cmd, "cmd", nil
+	case "darwin":
+		script, err := s.ShellScript(false)
+		if err != nil {
+			return nil, "", err
+		}
+		path, err := writeScript(script)
+		if err != nil {
+			return nil, "", err
+		}
+		line := "bash " + ShellQuote(path)
+		as := fmt.Sprintf(`tell application "Terminal" to do script "%s"`, appleScriptEscape(line))
+		return exec.Command("osascript", "-e", as), "macOS Terminal", nil
+	default:
+		var term []string
+		for _, t := range linuxTerminals {
+			if _, err := exec.LookPath(t[0]); err == nil {
+				term = t
+				break
+			}
+		}
+		if term == nil {
+			return nil, "", ErrNoTerminal
+		}
+		script, err := s.ShellScript(true)
+		if err != nil {
+			return nil, "", err
+		}
+		path, err := writeScript(script)
+		if err != nil {
+			return nil, "", err
+		}
+		args := append(append([]string(nil), term[1:]...), path)
+		return exec.Command(term[0], args...), term[0], nil
+	}
+}
+
+// ShellQuote returns a shell-escaped version of the string.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "'\\''") + "'"
+}
+
+func appleScriptEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
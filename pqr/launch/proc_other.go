@@ -0,0 +1,23 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+//go:build !unix && !windows
+
+package launch
+
+import (
+	"os"
+	"os/exec"
+)
+
+func newProcessGroup(cmd *exec.Cmd) {}
+
+// terminateTree kills pid; there are no process groups to reach its children.
+func terminateTree(pid int) error { return killTree(pid) }
+
+func killTree(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
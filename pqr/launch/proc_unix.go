@@ -0,0 +1,35 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+//go:build unix
+
+package launch
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// newProcessGroup starts cmd in a process group of its own, so that stopping
+// it reaches every process the script started.
+func newProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// terminateTree asks the process group led by pid to exit with SIGTERM.
+func terminateTree(pid int) error {
+	return signalGroup(pid, syscall.SIGTERM)
+}
+
+// killTree kills the process group led by pid with SIGKILL.
+func killTree(pid int) error {
+	return signalGroup(pid, syscall.SIGKILL)
+}
+
+// signalGroup sends sig to the process group led by pid. A group that is
+// already gone is not an error.
+func signalGroup(pid int, sig syscall.Signal) error {
+	if err := syscall.Kill(-pid, sig); err != nil && err != syscall.ESRCH {
+		return err
+	}
+	return nil
+}
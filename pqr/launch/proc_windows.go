@@ -0,0 +1,35 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package launch
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// newProcessGroup starts cmd in a process group of its own.
+func newProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+var generateConsoleCtrlEvent = syscall.NewLazyDLL("kernel32.dll").NewProc("GenerateConsoleCtrlEvent")
+
+// terminateTree asks the process group led by pid to exit with CTRL_BREAK.
+// Python sees it as SIGBREAK, which ends the script at once unless it sets a
+// handler with signal.signal; only CTRL_C raises KeyboardInterrupt, and that
+// cannot be sent to a process group of its own. Without a console to share,
+// it falls back to taskkill /T, which only reaches processes with a window;
+// Stop kills what is left after StopGrace.
+func terminateTree(pid int) error {
+	const ctrlBreakEvent = 1
+	if ok, _, _ := generateConsoleCtrlEvent.Call(ctrlBreakEvent, uintptr(pid)); ok != 0 {
+		return nil
+	}
+	return exec.Command("taskkill", "/T", "/PID", strconv.Itoa(pid)).Run()
+}
+
+// killTree ends pid and every process it started with taskkill /T /F.
+func killTree(pid int) error {
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(pid)).Run()
+}
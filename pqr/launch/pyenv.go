@@ -0,0 +1,240 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package launch
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PythonVersionFile is the pyenv file that pins a Python version for a
+// directory tree.
+const PythonVersionFile = ".python-version"
+
+// FindPythonVersion walks up from dir and returns the first version in the
+// nearest .python-version file. "system" counts as no version.
+func FindPythonVersion(dir string) string {
+	for {
+		if v := readPythonVersion(filepath.Join(dir, PythonVersionFile)); v != "" {
+			if v == "system" {
+				return ""
+			}
+			return v
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// readPythonVersion returns the first word of the file that is not a
+// comment, or "".
+func readPythonVersion(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 && !strings.HasPrefix(fields[0], "#") {
+			return fields[0]
+		}
+	}
+	return ""
+}
+
+// versionManager is a tool that keeps one directory per installed Python.
+type versionManager struct {
+	name   string
+	dir    func(home string) string // directory holding the installs
+	prefix string                   // install directory name before the version
+	python []string                 // interpreter paths inside an install
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// versionManagers are tried in order: pyenv, asdf, then uv-managed Pythons.
+var versionManagers = []versionManager{
+	{
+		name: "pyenv",
+		dir: func(home string) string {
+			if runtime.GOOS == "windows" {
+				return filepath.Join(envOr("PYENV_ROOT", filepath.Join(home, ".pyenv", "pyenv-win")), "versions")
+			}
+			return filepath.Join(envOr("PYENV_ROOT", filepath.Join(home, ".pyenv")), "versions")
+		},
+		python: []string{"bin/python", "python.exe"},
+	},
+	{
+		name: "asdf",
+		dir: func(home string) string {
+			return filepath.Join(envOr("ASDF_DATA_DIR", filepath.Join(home, ".asdf")), "installs", "python")
+		},
+		python: []string{"bin/python"},
+	},
+	{
+		name: "uv",
+		dir: func(home string) string {
+			if dir := os.Getenv("UV_PYTHON_INSTALL_DIR"); dir != "" {
+				return dir
+			}
+			if runtime.GOOS == "windows" {
+				return filepath.Join(envOr("APPDATA", home), "uv", "python")
+			}
+			return filepath.Join(envOr("XDG_DATA_HOME", filepath.Join(home, ".local", "share")), "uv", "python")
+		},
+		prefix: "cpython-",
+		python: []string{"bin/python3", "python.exe"},
+	},
+}
+
+// PythonForVersion finds an installed interpreter for a .python-version
+// entry such as "3.11.4" or "3.11" (the newest 3.11.x), looking at pyenv,
+// asdf and uv-managed installs in that order. It returns the interpreter and
+// the name of the tool that provided it.
+func PythonForVersion(version string) (python, manager string) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", ""
+	}
+	for _, m := range versionManagers {
+		if p := m.find(m.dir(home), version); p != "" {
+			return p, m.name
+		}
+	}
+	return "", ""
+}
+
+// InstalledPythons returns the interpreters of every pyenv, asdf and
+// uv-managed install, newest first within each tool.
+func InstalledPythons() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	var pythons []string
+	for _, m := range versionManagers {
+		pythons = append(pythons, m.all(m.dir(home))...)
+	}
+	return pythons
+}
+
+// all returns the interpreter of every install in dir, newest first.
+func (m versionManager) all(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), m.prefix) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return compareVersions(versionOf(names[i], m.prefix), versionOf(names[j], m.prefix)) > 0
+	})
+	var pythons []string
+	for _, name := range names {
+		if p := m.interpreter(filepath.Join(dir, name)); p != "" {
+			pythons = append(pythons, p)
+		}
+	}
+	return pythons
+}
+
+// find returns the interpreter of the install in dir named after version
+// exactly, or else of the newest install whose version starts with it.
+func (m versionManager) find(dir, version string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	var matches []string
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), m.prefix) {
+			continue
+		}
+		if v := versionOf(e.Name(), m.prefix); v == version || strings.HasPrefix(v, version+".") {
+			matches = append(matches, e.Name())
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		vi, vj := versionOf(matches[i], m.prefix), versionOf(matches[j], m.prefix)
+		if (vi == version) != (vj == version) {
+			return vi == version
+		}
+		return compareVersions(vi, vj) > 0
+	})
+	for _, name := range matches {
+		if p := m.interpreter(filepath.Join(dir, name)); p != "" {
+			return p
+		}
+	}
+	return ""
+}
+
+// interpreter returns the interpreter inside the install at dir, or "".
+func (m versionManager) interpreter(dir string) string {
+	for _, rel := range m.python {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			return p
+		}
+	}
+	return ""
+}
+
+// versionOf returns the version in an install directory name, e.g. "3.11.9"
+// for "3.11.9" or, with prefix "cpython-", "cpython-3.11.9-linux-x86_64-gnu".
+func versionOf(name, prefix string) string {
+	v := strings.TrimPrefix(name, prefix)
+	if prefix != "" {
+		v, _, _ = strings.Cut(v, "-")
+	}
+	return v
+}
+
+// compareVersions compares dotted versions numerically, part by part, and
+// returns -1, 0 or 1. Missing parts count as 0; non-numeric parts compare as
+// text.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		x, y := "0", "0"
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		switch {
+		case xerr == nil && yerr == nil && xn != yn:
+			if xn < yn {
+				return -1
+			}
+			return 1
+		case (xerr != nil || yerr != nil) && x != y:
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,338 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package launch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"pqr"
+)
+
+// Interpreter is the Python a script runs with and how it was chosen.
+type Interpreter struct {
+	Python string   // path, or a command name looked up in PATH
+	Venv   string   // root of the virtual environment Python belongs to, or ""
+	Conda  CondaEnv // conda environment Python belongs to; Prefix is "" without one
+	Source string   // rule that picked Python: "#pqr", "conda(base)", "Auto(.venv)", "Default", ...
+}
+
+// VenvDirs are the directory names searched for a virtual environment, in
+// order, in the script's directory and its parents.
+var VenvDirs = []string{".venv", "venv", "env"}
+
+// VenvSearchDepth is how many directories FindVenv looks at, the script's
+// own included.
+const VenvSearchDepth = 5
+
+// DefaultPython is the interpreter used when nothing else is configured.
+func DefaultPython() string {
+	if runtime.GOOS == "windows" {
+		return "python"
+	}
+	return "/usr/bin/python3"
+}
+
+// Resolver holds the per-machine settings interpreter resolution uses.
+type Resolver struct {
+	Default string            // global interpreter setting; "" means DefaultPython
+	Aliases map[string]string // named interpreters for py=, name to path
+
+	// Projects maps project directories to the interpreter for scripts
+	// below them; the nearest mapped ancestor of a script wins.
+	Projects map[string]string
+
+	// SkipConstraint leaves out the version check, for runners that pick
+	// their own Python (uv run --script, poetry, ...).
+	SkipConstraint bool
+}
+
+// UnknownAliasError is returned for a py= name missing from
+// Resolver.Aliases, so that the UI can offer to map it.
+type UnknownAliasError struct {
+	Name string
+}
+
+func (e *UnknownAliasError) Error() string {
+	return fmt.Sprintf("py=%s: no interpreter named %q on this machine", e.Name, e.Name)
+}
+
+// NoInterpreterError is returned when no known interpreter satisfies the
+// script's version constraint.
+type NoInterpreterError struct {
+	Constraint string
+}
+
+func (e *NoInterpreterError) Error() string {
+	return "no interpreter satisfies " + e.Constraint
+}
+
+// Resolve picks the interpreter for the script at scriptPath: the #pqr
+// interpreter for goos, the py= alias, the conda= environment, a virtual
+// environment found by FindVenv, the interpreter of the nearest directory in
+// r.Projects, the version pinned by a .python-version
+// above the script, the environment named by an environment.yml above the
+// script, and finally r.Default. A relative #pqr path starts at the script's
+// directory.
+//
+// When the script declares a version constraint (py>=3.11 or PEP 723
+// requires-python), the pick must satisfy it. An explicit choice (#pqr path,
+// py= name, conda=) that does not is an error; otherwise the first match
+// among the default, the venv, pyenv/asdf/uv installs and python3.X on PATH
+// is used, and NoInterpreterError is returned when there is none.
+//
+// Resolve may probe interpreters and list conda environments, which can take
+// seconds; UIs should call it off the main goroutine.
+func (r Resolver) Resolve(h pqr.Header, goos, scriptPath string) (Interpreter, error) {
+	in, explicit, err := r.resolve(h, goos, scriptPath)
+	spec := h.PythonConstraint()
+	if err != nil || spec == "" || r.SkipConstraint {
+		return in, err
+	}
+	c, err := ParseVersionConstraint(spec)
+	if err != nil {
+		return Interpreter{}, err
+	}
+	info, err := Probe(in.Python)
+	if err == nil && c.Allows(info.Version) {
+		return in, nil
+	}
+	if explicit {
+		if err != nil {
+			return Interpreter{}, err
+		}
+		return Interpreter{}, fmt.Errorf("%s (%s) is Python %s, which does not satisfy %s", in.Python, in.Source, info.Version, spec)
+	}
+
+	source := "py"
+	if !pqr.IsVersionConstraint(h.Py) {
+		source = "requires-python"
+	}
+	if strings.IndexByte("<>!~", spec[0]) < 0 {
+		source += "="
+	}
+	// Probe every candidate at once, as Discover does, then take the first
+	// match in order of preference.
+	candidates := r.candidates(in.Python, filepath.Dir(scriptPath))
+	ok := make([]bool, len(candidates))
+	var wg sync.WaitGroup
+	for i, python := range candidates {
+		wg.Add(1)
+		go func(i int, python string) {
+			defer wg.Done()
+			info, err := Probe(python)
+			ok[i] = err == nil && c.Allows(info.Version)
+		}(i, python)
+	}
+	wg.Wait()
+	for i, python := range candidates {
+		if ok[i] {
+			return withEnv(Interpreter{Python: python, Source: source + spec}), nil
+		}
+	}
+	return Interpreter{}, &NoInterpreterError{Constraint: spec}
+}
+
+// resolve applies the rules in order; explicit reports whether the header
+// chose the interpreter.
+func (r Resolver) resolve(h pqr.Header, goos, scriptPath string) (in Interpreter, explicit bool, err error) {
+	scriptDir := filepath.Dir(scriptPath)
+	if python := h.Interpreter(goos); python != "" {
+		if strings.ContainsAny(python, `/\`) && !filepath.IsAbs(python) {
+			python = filepath.Join(scriptDir, python)
+		}
+		return withEnv(Interpreter{Python: python, Source: "#pqr"}), true, nil
+	}
+	if alias := h.PyAlias(); alias != "" {
+		python, ok := r.Aliases[alias]
+		if !ok {
+			return Interpreter{}, true, &UnknownAliasError{Name: alias}
+		}
+		return withEnv(Interpreter{Python: python, Source: "py=" + alias}), true, nil
+	}
+	if h.Conda != "" {
+		env, err := FindCondaEnv(h.Conda)
+		if err != nil {
+			return Interpreter{}, true, err
+		}
+		return Interpreter{Python: env.Python(), Conda: env, Source: "conda(" + env.Name + ")"}, true, nil
+	}
+	if root, python := FindVenv(scriptDir); root != "" {
+		return Interpreter{Python: python, Venv: root, Source: "Auto(" + filepath.Base(root) + ")"}, false, nil
+	}
+	if dir, python := r.projectPython(scriptDir); python != "" {
+		return withEnv(Interpreter{Python: python, Source: "Project(" + filepath.Base(dir) + ")"}), false, nil
+	}
+	if python, source := pinnedPython(scriptDir); python != "" {
+		return withEnv(Interpreter{Python: python, Source: source}), false, nil
+	}
+	if env, ok := environmentFileEnv(scriptDir); ok {
+		return Interpreter{Python: env.Python(), Conda: env, Source: "environment.yml(" + env.Name + ")"}, false, nil
+	}
+	if r.Default != "" {
+		return withEnv(Interpreter{Python: r.Default, Source: "Default"}), false, nil
+	}
+	return withEnv(Interpreter{Python: DefaultPython(), Source: "Default"}), false, nil
+}
+
+// withEnv fills in the venv or conda environment in.Python belongs to.
+func withEnv(in Interpreter) Interpreter {
+	if in.Venv = VenvOf(in.Python); in.Venv == "" {
+		in.Conda, _ = CondaOf(in.Python)
+	}
+	return in
+}
+
+// candidates lists the interpreters a version constraint may pick from, in
+// order of preference, skipping first (already tried).
+func (r Resolver) candidates(first, scriptDir string) []string {
+	list := []string{r.Default, DefaultPython()}
+	if _, python := FindVenv(scriptDir); python != "" {
+		list = append(list, python)
+	}
+	list = append(list, InstalledPythons()...)
+	for minor := 20; minor >= 6; minor-- {
+		if p, err := FindProgram(fmt.Sprintf("python3.%d", minor)); err == nil {
+			list = append(list, p)
+		}
+	}
+	seen := map[string]bool{first: true, "": true}
+	var out []string
+	for _, p := range list {
+		if !seen[p] {
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// projectPython returns the nearest directory at or above dir that is in
+// r.Projects, with its interpreter.
+func (r Resolver) projectPython(dir string) (project, python string) {
+	if len(r.Projects) == 0 {
+		return "", ""
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	for {
+		if python := r.Projects[dir]; python != "" {
+			return dir, python
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+// pinnedPython returns the installed interpreter for the nearest
+// .python-version above dir and a source such as "pyenv(3.11.4)".
+func pinnedPython(dir string) (python, source string) {
+	version := FindPythonVersion(dir)
+	if version == "" {
+		return "", ""
+	}
+	python, manager := PythonForVersion(version)
+	if python == "" {
+		return "", ""
+	}
+	return python, manager + "(" + version + ")"
+}
+
+// environmentFileEnv returns the known environment named by the nearest
+// environment file above dir.
+func environmentFileEnv(dir string) (CondaEnv, bool) {
+	name := FindEnvironmentFile(dir)
+	if name == "" {
+		return CondaEnv{}, false
+	}
+	env, err := FindCondaEnv(name)
+	return env, err == nil
+}
+
+// FindVenv walks up from dir for at most VenvSearchDepth directories and
+// returns the first virtual environment in VenvDirs that has an interpreter,
+// together with that interpreter.
+func FindVenv(dir string) (root, python string) {
+	for i := 0; i < VenvSearchDepth; i++ {
+		for _, name := range VenvDirs {
+			candidate := filepath.Join(dir, name)
+			if p := VenvPython(candidate); p != "" {
+				return candidate, p
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return "", ""
+}
+
+// VenvPython returns the interpreter of the virtual environment at root,
+// trying the POSIX bin/ and the Windows Scripts\ layouts, or "" when root
+// has none.
+func VenvPython(root string) string {
+	for _, rel := range []string{"bin/python", "bin/python3", "Scripts/python.exe"} {
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			return p
+		}
+	}
+	return ""
+}
+
+// VenvOf returns the root of the virtual environment python lives in, found
+// through the pyvenv.cfg next to its bin or Scripts directory, or "".
+func VenvOf(python string) string {
+	if !strings.ContainsAny(python, `/\`) {
+		return ""
+	}
+	abs, err := filepath.Abs(python)
+	if err != nil {
+		return ""
+	}
+	root := filepath.Dir(filepath.Dir(abs))
+	if _, err := os.Stat(filepath.Join(root, "pyvenv.cfg")); err != nil {
+		return ""
+	}
+	return root
+}
+
+// Environ returns the variables that activate in.Venv (VIRTUAL_ENV and PATH
+// with the interpreter's bin or Scripts directory first) or in.Conda. It
+// returns nil without either.
+func (in Interpreter) Environ() []string {
+	if in.Venv == "" {
+		if in.Conda.Prefix != "" {
+			return in.Conda.Environ()
+		}
+		return nil
+	}
+	binDir := filepath.Dir(in.Python)
+	if abs, err := filepath.Abs(binDir); err == nil {
+		binDir = abs
+	}
+	return []string{
+		"VIRTUAL_ENV=" + in.Venv,
+		"PATH=" + binDir + string(os.PathListSeparator) + os.Getenv("PATH"),
+	}
+}
+
+// Timeout returns the timeout for a script: its timeout= header value, or
+// def (the launcher's default setting) when the header has none.
+func Timeout(h pqr.Header, def string) (time.Duration, error) {
+	if h.Timeout != "" {
+		return pqr.ParseTimeout(h.Timeout)
+	}
+	return pqr.ParseTimeout(def)
+}
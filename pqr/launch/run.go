@@ -0,0 +1,246 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package launch
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stream names the output stream a chunk of output came from.
+type Stream int
+
+const (
+	Stdout Stream = iota
+	Stderr
+)
+
+// StopGrace is how long Stop lets a script exit after SIGTERM before it
+// kills what is left of the process tree.
+const StopGrace = 3 * time.Second
+
+// Run is a started script (Spec.Start) or terminal window
+// (Spec.StartTerminal). The process runs in a process group of its own, so
+// Stop and Kill reach everything it started.
+type Run struct {
+	Started time.Time
+	Args    []string // command line of the process
+
+	cmd      *exec.Cmd
+	done     chan struct{}
+	err      error
+	exitCode int
+	ended    time.Time
+	timedOut atomic.Bool
+	stdin    io.WriteCloser
+}
+
+// Start starts the script without a terminal and calls output with every
+// chunk it writes to stdout or stderr, one call at a time, from another
+// goroutine. The chunk is only valid during the call. Standard input is
+// s.Stdin or else a pipe the caller writes to through Run.Stdin. With
+// s.Timeout set, the run is stopped (see Stop) once it has run that long and
+// TimedOut reports true.
+func (s Spec) Start(output func(Stream, []byte)) (*Run, error) {
+	cmd := s.Command()
+	var stdin io.WriteCloser
+	if s.Stdin != "" {
+		f, err := os.Open(s.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close() // the child has its own copy once started
+		cmd.Stdin = f
+	} else {
+		pipe, err := cmd.StdinPipe()
+		if err != nil {
+			return nil, err
+		}
+		stdin = pipe
+	}
+	r, err := start(cmd, output)
+	if err != nil {
+		return nil, err
+	}
+	r.stdin = stdin
+	if s.Timeout > 0 {
+		go r.watch(s.Timeout)
+	}
+	return r, nil
+}
+
+// watch stops r after timeout unless it exits first. Stop kills the tree
+// after StopGrace; should even that fail, the kill is retried every
+// StopGrace until the script is gone.
+func (r *Run) watch(timeout time.Duration) {
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	select {
+	case <-r.done:
+		return
+	case <-t.C:
+	}
+	r.timedOut.Store(true)
+	err := r.Stop()
+	for err != nil {
+		select {
+		case <-r.done:
+			return
+		case <-time.After(StopGrace):
+			err = r.Kill()
+		}
+	}
+}
+
+// StartTerminal starts the script in a new terminal window (see
+// TerminalCommand) and returns the terminal's name. The Run follows the
+// terminal process; terminals that hand the window to another process (macOS
+// Terminal, gnome-terminal) finish right away.
+func (s Spec) StartTerminal() (*Run, string, error) {
+	cmd, name, err := s.TerminalCommand()
+	if err != nil {
+		return nil, "", err
+	}
+	r, err := start(cmd, nil)
+	return r, name, err
+}
+
+// start runs cmd in its own process group. Without output the process
+// writes to the launcher's stdout and stderr.
+func start(cmd *exec.Cmd, output func(Stream, []byte)) (*Run, error) {
+	newProcessGroup(cmd)
+	var readers []io.Reader
+	if output == nil {
+		cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	} else {
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, err
+		}
+		stderr, err := cmd.StderrPipe()
+		if err != nil {
+			return nil, err
+		}
+		readers = []io.Reader{stdout, stderr}
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	r := &Run{Started: time.Now(), Args: cmd.Args, cmd: cmd, done: make(chan struct{})}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	pump := func(stream Stream, rd io.Reader) {
+		defer wg.Done()
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := rd.Read(buf)
+			if n > 0 {
+				mu.Lock()
+				output(stream, buf[:n])
+				mu.Unlock()
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+	for i, rd := range readers {
+		wg.Add(1)
+		go pump(Stream(i), rd)
+	}
+
+	go func() {
+		wg.Wait() // all output is read before Wait closes the pipes
+		r.err = cmd.Wait()
+		r.ended = time.Now()
+		r.exitCode = -1
+		if cmd.ProcessState != nil {
+			r.exitCode = cmd.ProcessState.ExitCode()
+		}
+		close(r.done)
+	}()
+	return r, nil
+}
+
+// Pid returns the process ID, which is also the process group ID on Unix.
+func (r *Run) Pid() int { return r.cmd.Process.Pid }
+
+// Stop asks the script and every process it started to exit (SIGTERM to the
+// process group, CTRL_BREAK on Windows) and kills the tree if it is still
+// there after StopGrace. When the request cannot be sent the tree is killed
+// right away, and only a failed kill is returned. It does nothing once the
+// script has exited.
+func (r *Run) Stop() error {
+	if r.exited() {
+		return nil
+	}
+	go func() {
+		select {
+		case <-r.done:
+		case <-time.After(StopGrace):
+			_ = r.Kill()
+		}
+	}()
+	if err := terminateTree(r.Pid()); err != nil {
+		// nothing asked the script to exit: kill it now rather than wait
+		return r.Kill()
+	}
+	return nil
+}
+
+// Kill kills the script and every process it started right away (SIGKILL
+// to the process group).
+func (r *Run) Kill() error {
+	if r.exited() {
+		return nil
+	}
+	return killTree(r.Pid())
+}
+
+func (r *Run) exited() bool {
+	select {
+	case <-r.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Stdin returns the pipe to the script's standard input, or nil when it
+// reads from Spec.Stdin or runs in a terminal. Closing it sends EOF.
+func (r *Run) Stdin() io.WriteCloser { return r.stdin }
+
+// TimedOut reports whether the run was stopped because it reached
+// Spec.Timeout.
+func (r *Run) TimedOut() bool { return r.timedOut.Load() }
+
+// Done is closed once the script has exited and its output is delivered.
+func (r *Run) Done() <-chan struct{} { return r.done }
+
+// Wait waits for the script to exit and returns the error of exec.Cmd.Wait,
+// such as an *exec.ExitError for a non-zero exit code.
+func (r *Run) Wait() error {
+	<-r.done
+	return r.err
+}
+
+// ExitCode returns the exit code after Done, or -1 when the script was
+// killed by a signal.
+func (r *Run) ExitCode() int {
+	<-r.done
+	return r.exitCode
+}
+
+// Duration returns how long the script has run so far, or ran in total once
+// it is done.
+func (r *Run) Duration() time.Duration {
+	if r.exited() {
+		return r.ended.Sub(r.Started)
+	}
+	return time.Since(r.Started)
+}
@@ -0,0 +1,170 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package launch
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"pqr"
+)
+
+// Runner is a command template that starts a script. Template words are
+// expanded by Argv:
+//
+//	{python}  the resolved interpreter
+//	{flags}   pyflags=, one word each (dropped when empty)
+//	{script}  the script path
+//	{args}    args=, one word each (dropped when empty)
+//	{env}     the environment name of runners such as "conda:<env>"
+type Runner struct {
+	Name     string   // built-in name, or "custom"
+	Template []string // words with placeholders
+	Env      string   // value for {env}
+}
+
+// Runners are the built-in runners, selectable by name in runner= and in the
+// default-runner settings. "conda" takes the environment as "conda:<env>".
+var Runners = []Runner{
+	{Name: "python", Template: []string{"{python}", "{flags}", "{script}", "{args}"}},
+	{Name: "uv", Template: []string{"uv", "run", "python", "{flags}", "{script}", "{args}"}},
+	{Name: "poetry", Template: []string{"poetry", "run", "python", "{flags}", "{script}", "{args}"}},
+	{Name: "pipenv", Template: []string{"pipenv", "run", "python", "{flags}", "{script}", "{args}"}},
+	{Name: "hatch", Template: []string{"hatch", "run", "python", "{flags}", "{script}", "{args}"}},
+	{Name: "conda", Template: []string{"conda", "run", "--no-capture-output", "-n", "{env}", "python", "{flags}", "{script}", "{args}"}},
+}
+
+// RunnerNames returns the names of Runners, for pickers.
+func RunnerNames() []string {
+	names := make([]string, len(Runners))
+	for i, r := range Runners {
+		names[i] = r.Name
+	}
+	return names
+}
+
+// ParseRunner reads a runner= value or default-runner setting: "" (python),
+// a built-in name optionally followed by ":<env>", or a custom template that
+// contains {script}, e.g. "rye run python {script} {args}".
+func ParseRunner(value string) (Runner, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Runners[0], nil
+	}
+	if strings.Contains(value, "{script}") {
+		words, err := pqr.SplitArgs(value)
+		if err != nil {
+			return Runner{}, fmt.Errorf("runner: %v", err)
+		}
+		return Runner{Name: "custom", Template: words}, nil
+	}
+	name, env, _ := strings.Cut(value, ":")
+	for _, r := range Runners {
+		if strings.EqualFold(r.Name, name) {
+			r.Env = strings.TrimSpace(env)
+			return r, nil
+		}
+	}
+	return Runner{}, fmt.Errorf("runner: unknown runner %q (want %s, or a template with {script})", value, strings.Join(RunnerNames(), ", "))
+}
+
+// CommandRunner returns a runner that appends "script args..." to the
+// command line cmd, such as DefaultMetadataRunner. It has no {flags}, so
+// scripts with pyflags= cannot use it (see Argv).
+func CommandRunner(cmd string) (Runner, error) {
+	words, err := pqr.SplitArgs(cmd)
+	if err != nil {
+		return Runner{}, fmt.Errorf("runner: %v", err)
+	}
+	if len(words) == 0 {
+		return Runner{}, errors.New("runner: empty command")
+	}
+	return Runner{Name: words[0], Template: append(words, "{script}", "{args}")}, nil
+}
+
+// SelectRunner picks the runner for a script with header h on goos: runner=
+// first, then the interpreter when #pqr names one (a path or a py= name; a
+// py>= constraint only narrows the interpreter), then metadataRunner for
+// scripts with PEP 723 metadata (unless it is empty), and finally def, the
+// default-runner setting. A runner without an environment name takes conda=.
+//
+// A py= version constraint needs a runner that uses the resolved interpreter;
+// with one that picks its own Python (uv, poetry, ...) it could not be
+// checked, so that is an error.
+func SelectRunner(h pqr.Header, goos, def, metadataRunner string) (Runner, error) {
+	r, err := selectRunner(h, goos, def, metadataRunner)
+	if err != nil {
+		return r, err
+	}
+	if pqr.IsVersionConstraint(h.Py) && !r.UsesPython() {
+		spec := h.Py
+		if strings.IndexByte("<>!~", spec[0]) < 0 {
+			spec = "=" + spec
+		}
+		return Runner{}, fmt.Errorf("runner %s picks its own Python and cannot check py%s; set runner=python or remove the constraint", r.Name, spec)
+	}
+	if r.Env == "" {
+		r.Env = h.Conda
+	}
+	return r, nil
+}
+
+func selectRunner(h pqr.Header, goos, def, metadataRunner string) (Runner, error) {
+	switch {
+	case h.Runner != "":
+		return ParseRunner(h.Runner)
+	case h.Interpreter(goos) != "" || h.PyAlias() != "":
+		return Runners[0], nil
+	case h.Script != nil && metadataRunner != "":
+		return CommandRunner(metadataRunner)
+	}
+	return ParseRunner(def)
+}
+
+// UsesPython reports whether the template runs the resolved interpreter
+// itself rather than letting a tool pick one.
+func (r Runner) UsesPython() bool {
+	for _, w := range r.Template {
+		if strings.Contains(w, "{python}") {
+			return true
+		}
+	}
+	return false
+}
+
+// Argv expands the template. Unless the program is {python}, it is looked
+// up with FindProgram. Flags for a template without {flags} are an error
+// rather than being dropped.
+func (r Runner) Argv(python string, flags []string, script string, args []string) ([]string, error) {
+	repl := strings.NewReplacer("{python}", python, "{script}", script, "{env}", r.Env)
+	var argv []string
+	flagsUsed := false
+	for _, w := range r.Template {
+		switch w {
+		case "{flags}":
+			argv = append(argv, flags...)
+			flagsUsed = true
+		case "{args}":
+			argv = append(argv, args...)
+		default:
+			if strings.Contains(w, "{env}") && r.Env == "" {
+				return nil, fmt.Errorf("runner %s: no environment name (use %s:<env>)", r.Name, r.Name)
+			}
+			argv = append(argv, repl.Replace(w))
+		}
+	}
+	if len(argv) == 0 {
+		return nil, errors.New("runner: empty command")
+	}
+	if len(flags) > 0 && !flagsUsed {
+		return nil, fmt.Errorf("runner %s: pyflags= is not supported (the runner has no {flags}); set runner= to one that takes interpreter flags", r.Name)
+	}
+	if !strings.Contains(r.Template[0], "{python}") {
+		var err error
+		if argv[0], err = FindProgram(argv[0]); err != nil {
+			return nil, fmt.Errorf("runner %s: %w", r.Name, err)
+		}
+	}
+	return argv, nil
+}
@@ -0,0 +1,190 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package launch
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"pqr"
+)
+
+const pep723Block = "# /// script\n# requires-python = \">=3.10\"\n# dependencies = [\"rich\"]\n# ///\n"
+
+func TestSelectRunner(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		def      string
+		metadata string
+		want     []string // Template of the selected runner
+		wantEnv  string
+		wantErr  string
+	}{
+		{
+			name: "default",
+			want: Runners[0].Template,
+		},
+		{
+			name: "default setting",
+			def:  "uv",
+			want: Runners[1].Template,
+		},
+		{
+			name:     "PEP 723 uses the metadata runner",
+			src:      pep723Block,
+			metadata: "uv run --script",
+			want:     []string{"uv", "run", "--script", "{script}", "{args}"},
+		},
+		{
+			name:     "PEP 723 with requires-python uses the metadata runner",
+			src:      pep723Block,
+			metadata: "uv run --script",
+			want:     []string{"uv", "run", "--script", "{script}", "{args}"},
+		},
+		{
+			name:     "py constraint with the metadata runner",
+			src:      "#pqr py>=3.11\n" + pep723Block,
+			metadata: "uv run --script",
+			wantErr:  "runner uv picks its own Python and cannot check py>=3.11",
+		},
+		{
+			name:    "py constraint with a default tool runner",
+			src:     "#pqr py=3.12\n",
+			def:     "poetry",
+			wantErr: "runner poetry picks its own Python and cannot check py=3.12",
+		},
+		{
+			name: "py constraint with the python runner",
+			src:  "#pqr py>=3.11\n",
+			def:  "python",
+			want: Runners[0].Template,
+		},
+		{
+			name: "py constraint with a custom python template",
+			src:  "#pqr py>=3.11; runner={python} -X dev {script}\n",
+			def:  "uv",
+			want: []string{"{python}", "-X", "dev", "{script}"},
+		},
+		{
+			name:     "PEP 723 with py name uses python",
+			src:      "#pqr py=ds311\n" + pep723Block,
+			metadata: "uv run --script",
+			want:     Runners[0].Template,
+		},
+		{
+			name:     "PEP 723 with interpreter path uses python",
+			src:      "#pqr linux=/opt/py/bin/python\n" + pep723Block,
+			metadata: "uv run --script",
+			want:     Runners[0].Template,
+		},
+		{
+			name: "PEP 723 without metadata runner falls back to default",
+			src:  pep723Block,
+			def:  "poetry",
+			want: Runners[2].Template,
+		},
+		{
+			name:     "runner= wins",
+			src:      "#pqr runner=pipenv; linux=/opt/py/bin/python\n" + pep723Block,
+			metadata: "uv run --script",
+			want:     Runners[3].Template,
+		},
+		{
+			name:    "conda= fills the environment",
+			src:     "#pqr runner=conda; conda=ds\n",
+			want:    Runners[5].Template,
+			wantEnv: "ds",
+		},
+		{
+			name:    "runner environment wins over conda=",
+			src:     "#pqr runner=conda:ml; conda=ds\n",
+			want:    Runners[5].Template,
+			wantEnv: "ml",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := pqr.Parse(strings.NewReader(tt.src))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			r, err := SelectRunner(h, "linux", tt.def, tt.metadata)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("SelectRunner error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectRunner: %v", err)
+			}
+			if !reflect.DeepEqual(r.Template, tt.want) || r.Env != tt.wantEnv {
+				t.Errorf("SelectRunner = %q (env %q), want %q (env %q)", r.Template, r.Env, tt.want, tt.wantEnv)
+			}
+		})
+	}
+}
+
+func TestRunnerArgv(t *testing.T) {
+	custom, _ := ParseRunner("{python} -X dev {flags} {script} --run {args}")
+	metadata, _ := CommandRunner("uv run --script")
+	conda, _ := ParseRunner("conda")
+	tests := []struct {
+		name    string
+		r       Runner
+		flags   []string
+		args    []string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "python",
+			r:    Runners[0],
+			want: []string{"py", "s.py"},
+		},
+		{
+			name:  "python with flags and args",
+			r:     Runners[0],
+			flags: []string{"-u", "-X", "utf8"},
+			args:  []string{"a b", "c"},
+			want:  []string{"py", "-u", "-X", "utf8", "s.py", "a b", "c"},
+		},
+		{
+			name:  "custom template",
+			r:     custom,
+			flags: []string{"-u"},
+			args:  []string{"x"},
+			want:  []string{"py", "-X", "dev", "-u", "s.py", "--run", "x"},
+		},
+		{
+			name:    "flags without {flags}",
+			r:       metadata,
+			flags:   []string{"-u"},
+			wantErr: "pyflags= is not supported",
+		},
+		{
+			name:    "conda without environment",
+			r:       conda,
+			wantErr: "no environment name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.r.Argv("py", tt.flags, "s.py", tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Argv error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Argv: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Argv = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
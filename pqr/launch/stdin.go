@@ -0,0 +1,95 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package launch
+
+import (
+	"io"
+	"sync"
+)
+
+// StdinWriter feeds a run's stdin pipe from a goroutine of its own, in
+// order, so that a script that is not reading its input cannot block the
+// caller (a UI thread) once the pipe buffer is full.
+type StdinWriter struct {
+	mu      sync.Mutex
+	queue   []string
+	closing bool
+	wake    chan struct{}
+}
+
+// NewStdinWriter starts a writer for r.Stdin(). It returns nil when the run
+// has no stdin pipe. onErr is called from the writer goroutine with a failed
+// write or close; text still queued then is dropped. The writer stops when
+// the run ends.
+func NewStdinWriter(r *Run, onErr func(error)) *StdinWriter {
+	if r.Stdin() == nil {
+		return nil
+	}
+	return newStdinWriter(r.Stdin(), r.Done(), onErr)
+}
+
+func newStdinWriter(w io.WriteCloser, done <-chan struct{}, onErr func(error)) *StdinWriter {
+	s := &StdinWriter{wake: make(chan struct{}, 1)}
+	go s.loop(w, done, onErr)
+	return s
+}
+
+// Write queues text for the script and returns at once.
+func (s *StdinWriter) Write(text string) {
+	s.mu.Lock()
+	if !s.closing {
+		s.queue = append(s.queue, text)
+	}
+	s.mu.Unlock()
+	s.signal()
+}
+
+// Close sends EOF once the queued text is written. Later writes are dropped.
+func (s *StdinWriter) Close() {
+	s.mu.Lock()
+	s.closing = true
+	s.mu.Unlock()
+	s.signal()
+}
+
+func (s *StdinWriter) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *StdinWriter) loop(w io.WriteCloser, done <-chan struct{}, onErr func(error)) {
+	for {
+		select {
+		case <-s.wake:
+		case <-done:
+			return
+		}
+		for {
+			s.mu.Lock()
+			if len(s.queue) == 0 {
+				closing := s.closing
+				s.mu.Unlock()
+				if !closing {
+					break
+				}
+				if err := w.Close(); err != nil {
+					onErr(err)
+				}
+				return
+			}
+			text := s.queue[0]
+			s.queue = s.queue[1:]
+			s.mu.Unlock()
+
+			if _, err := io.WriteString(w, text); err != nil {
+				onErr(err)
+				s.mu.Lock()
+				s.queue, s.closing = nil, true
+				s.mu.Unlock()
+				return
+			}
+		}
+	}
+}
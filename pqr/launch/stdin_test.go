@@ -0,0 +1,67 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package launch
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestStdinWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{"nothing", nil, ""},
+		{"in order", []string{"one\n", "two\n", "three\n"}, "one\ntwo\nthree\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, w := io.Pipe()
+			s := newStdinWriter(w, make(chan struct{}), func(err error) { t.Errorf("onErr: %v", err) })
+
+			// Nobody reads yet, so a blocking writer would hang here.
+			returned := make(chan struct{})
+			go func() {
+				for _, text := range tt.writes {
+					s.Write(text)
+				}
+				s.Close()
+				s.Write("after close\n")
+				close(returned)
+			}()
+			select {
+			case <-returned:
+			case <-time.After(time.Second):
+				t.Fatal("Write blocked while the reader was not reading")
+			}
+
+			got, err := io.ReadAll(r) // ends at the EOF Close sends
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("read %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStdinWriterError(t *testing.T) {
+	r, w := io.Pipe()
+	r.CloseWithError(errors.New("gone"))
+	errc := make(chan error, 1)
+	s := newStdinWriter(w, make(chan struct{}), func(err error) { errc <- err })
+	s.Write("x\n")
+	select {
+	case err := <-errc:
+		if err == nil || err.Error() != "gone" {
+			t.Errorf("onErr(%v), want gone", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("onErr was not called")
+	}
+}
@@ -0,0 +1,209 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package launch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VersionConstraint is a PEP 440 version specifier set such as
+// ">=3.11,<3.13". A bare version such as "3.12" means "==3.12.*".
+type VersionConstraint struct {
+	text    string
+	clauses []versionClause
+}
+
+type versionClause struct {
+	op       string // ">=", "<=", ">", "<", "==", "!=", "~="
+	version  string
+	wildcard bool // "==3.11.*"
+}
+
+// ParseVersionConstraint parses s. Clauses are separated by commas.
+func ParseVersionConstraint(s string) (VersionConstraint, error) {
+	c := VersionConstraint{text: strings.TrimSpace(s)}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		op := ""
+		for _, o := range []string{">=", "<=", "==", "!=", "~=", ">", "<", "="} {
+			if strings.HasPrefix(part, o) {
+				op = o
+				break
+			}
+		}
+		v := strings.TrimSpace(part[len(op):])
+		switch op {
+		case "":
+			op, v = "==", v+".*"
+		case "=":
+			op = "=="
+		}
+		cl := versionClause{op: op, version: v}
+		if cl.version, cl.wildcard = strings.CutSuffix(v, ".*"); cl.wildcard && op != "==" && op != "!=" {
+			return VersionConstraint{}, fmt.Errorf("invalid version constraint %q: .* only goes with == or !=", part)
+		}
+		if !validVersion(cl.version) {
+			return VersionConstraint{}, fmt.Errorf("invalid version constraint %q", part)
+		}
+		if op == "~=" && !strings.Contains(cl.version, ".") {
+			return VersionConstraint{}, fmt.Errorf("invalid version constraint %q: ~= needs at least two parts", part)
+		}
+		c.clauses = append(c.clauses, cl)
+	}
+	if len(c.clauses) == 0 {
+		return VersionConstraint{}, fmt.Errorf("empty version constraint %q", s)
+	}
+	return c, nil
+}
+
+func validVersion(v string) bool {
+	if v == "" {
+		return false
+	}
+	for _, part := range strings.Split(v, ".") {
+		if part == "" || strings.Trim(part, "0123456789") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (c VersionConstraint) String() string { return c.text }
+
+// Allows reports whether version (e.g. "3.11.7") satisfies every clause.
+func (c VersionConstraint) Allows(version string) bool {
+	for _, cl := range c.clauses {
+		if !cl.allows(version) {
+			return false
+		}
+	}
+	return true
+}
+
+func (cl versionClause) allows(v string) bool {
+	cmp := compareVersions(v, cl.version)
+	switch cl.op {
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	case "==":
+		if cl.wildcard {
+			return hasVersionPrefix(v, cl.version)
+		}
+		return cmp == 0
+	case "!=":
+		if cl.wildcard {
+			return !hasVersionPrefix(v, cl.version)
+		}
+		return cmp != 0
+	case "~=":
+		prefix := cl.version[:strings.LastIndexByte(cl.version, '.')]
+		return cmp >= 0 && hasVersionPrefix(v, prefix)
+	}
+	return false
+}
+
+// hasVersionPrefix reports whether v is prefix or starts with prefix + ".".
+func hasVersionPrefix(v, prefix string) bool {
+	return v == prefix || strings.HasPrefix(v, prefix+".")
+}
+
+// PythonInfo is what Probe learns about an interpreter.
+type PythonInfo struct {
+	Version        string // e.g. "3.11.7"
+	Implementation string // e.g. "CPython", "PyPy"
+	Venv           bool   // running inside a virtual environment
+}
+
+// String describes info for the settings UIs, e.g.
+// "Python 3.11.7 (CPython, venv)".
+func (info PythonInfo) String() string {
+	s := "Python " + info.Version + " (" + info.Implementation
+	if info.Venv {
+		s += ", venv"
+	}
+	return s + ")"
+}
+
+// probeScript prints what PythonInfo holds, one value per line.
+const probeScript = `import sys, platform
+print("%d.%d.%d" % sys.version_info[:3])
+print(platform.python_implementation())
+print(sys.prefix != getattr(sys, "base_prefix", sys.prefix))`
+
+// ProbeTimeout bounds how long Probe waits for an interpreter.
+const ProbeTimeout = 5 * time.Second
+
+var probeCache struct {
+	sync.Mutex
+	m map[string]probeEntry
+}
+
+type probeEntry struct {
+	stamp string // size and modification time of the file probed
+	info  PythonInfo
+	err   error
+}
+
+// Probe runs python with -c to find out its version, implementation and
+// whether it is a venv. Results are cached for as long as the interpreter
+// file is unchanged.
+func Probe(python string) (PythonInfo, error) {
+	path, err := exec.LookPath(python)
+	if err != nil {
+		return PythonInfo{}, err
+	}
+	stamp := ""
+	if fi, err := os.Stat(path); err == nil {
+		stamp = fmt.Sprint(fi.Size(), fi.ModTime().UnixNano())
+	}
+
+	probeCache.Lock()
+	if e, ok := probeCache.m[path]; ok && e.stamp == stamp {
+		probeCache.Unlock()
+		return e.info, e.err
+	}
+	probeCache.Unlock()
+
+	info, err := probe(path)
+
+	probeCache.Lock()
+	if probeCache.m == nil {
+		probeCache.m = map[string]probeEntry{}
+	}
+	probeCache.m[path] = probeEntry{stamp: stamp, info: info, err: err}
+	probeCache.Unlock()
+	return info, err
+}
+
+func probe(path string) (PythonInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ProbeTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "-c", probeScript).Output()
+	if err != nil {
+		return PythonInfo{}, fmt.Errorf("%s: not a working Python interpreter (%v)", path, err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != 3 || !validVersion(strings.TrimSpace(lines[0])) {
+		return PythonInfo{}, fmt.Errorf("%s: unexpected probe output %q", path, out)
+	}
+	return PythonInfo{
+		Version:        strings.TrimSpace(lines[0]),
+		Implementation: strings.TrimSpace(lines[1]),
+		Venv:           strings.TrimSpace(lines[2]) == "True",
+	}, nil
+}
@@ -0,0 +1,51 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package launch
+
+import "testing"
+
+func TestParseVersionConstraint(t *testing.T) {
+	tests := []struct {
+		spec    string
+		allow   []string
+		deny    []string
+		wantErr bool
+	}{
+		{spec: ">=3.11", allow: []string{"3.11.0", "3.12.4", "4.0"}, deny: []string{"3.10.9", "2.7.18"}},
+		{spec: ">=3.11,<3.13", allow: []string{"3.11.7", "3.12.0"}, deny: []string{"3.13.0", "3.10.0"}},
+		{spec: "3.12", allow: []string{"3.12", "3.12.3"}, deny: []string{"3.1", "3.13.0", "3.120"}},
+		{spec: "==3.11.*", allow: []string{"3.11.2"}, deny: []string{"3.12.0"}},
+		{spec: "=3.11.4", allow: []string{"3.11.4"}, deny: []string{"3.11.5"}},
+		{spec: "!=3.12.*", allow: []string{"3.11.9", "3.13.0"}, deny: []string{"3.12.1"}},
+		{spec: "~=3.11", allow: []string{"3.11.0", "3.12.1"}, deny: []string{"3.10.9", "4.0"}},
+		{spec: "~=3.11.2", allow: []string{"3.11.2", "3.11.9"}, deny: []string{"3.11.1", "3.12.0"}},
+		{spec: "> 3.9 , <= 3.11", allow: []string{"3.10.1", "3.11"}, deny: []string{"3.9", "3.11.1"}},
+		{spec: "", wantErr: true},
+		{spec: ">=3.x", wantErr: true},
+		{spec: ">=3.11.*", wantErr: true},
+		{spec: "~=3", wantErr: true},
+	}
+	for _, tt := range tests {
+		c, err := ParseVersionConstraint(tt.spec)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseVersionConstraint(%q) = %v, want an error", tt.spec, c)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseVersionConstraint(%q): %v", tt.spec, err)
+			continue
+		}
+		for _, v := range tt.allow {
+			if !c.Allows(v) {
+				t.Errorf("%q does not allow %s", tt.spec, v)
+			}
+		}
+		for _, v := range tt.deny {
+			if c.Allows(v) {
+				t.Errorf("%q allows %s", tt.spec, v)
+			}
+		}
+	}
+}
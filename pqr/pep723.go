@@ -0,0 +1,71 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package pqr
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ScriptMetadata is the PEP 723 inline metadata of a script:
+//
+//	# /// script
+//	# requires-python = ">=3.11"
+//	# dependencies = ["requests<3", "rich"]
+//	# ///
+type ScriptMetadata struct {
+	RequiresPython string   `toml:"requires-python"`
+	Dependencies   []string `toml:"dependencies"`
+}
+
+// PEP 723 block delimiters.
+const (
+	MetadataStart = "# /// script"
+	MetadataEnd   = "# ///"
+)
+
+// metadataBlock collects the comment lines that follow "# /// script".
+type metadataBlock struct {
+	line  int      // line number of "# /// script"
+	lines []string // following "#" and "# ..." lines, \r removed
+}
+
+// isMetadataLine reports whether s may continue a metadata block.
+func isMetadataLine(s string) bool {
+	return s == "#" || strings.HasPrefix(s, "# ")
+}
+
+// decode parses the block. As in PEP 723 the block ends at the last "# ///"
+// of the comment run; text before it, minus the "# " prefix, is TOML.
+func (b *metadataBlock) decode() (*ScriptMetadata, *SyntaxError) {
+	end := -1
+	for i, l := range b.lines {
+		if l == MetadataEnd {
+			end = i
+		}
+	}
+	if end < 0 {
+		return nil, &SyntaxError{Line: b.line, Column: 1, Msg: "unterminated " + MetadataStart + " block (missing " + MetadataEnd + ")"}
+	}
+	var src strings.Builder
+	for _, l := range b.lines[:end] {
+		if len(l) > 2 {
+			src.WriteString(l[2:])
+		}
+		src.WriteByte('\n')
+	}
+	var meta ScriptMetadata
+	if _, err := toml.Decode(src.String(), &meta); err != nil {
+		serr := &SyntaxError{Line: b.line, Column: 1, Msg: "script metadata: " + err.Error()}
+		var perr toml.ParseError
+		if errors.As(err, &perr) {
+			serr.Line = b.line + perr.Position.Line
+			serr.Column = perr.Position.Col + 2
+			serr.Msg = "script metadata: " + perr.Message
+		}
+		return nil, serr
+	}
+	return &meta, nil
+}
@@ -0,0 +1,72 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package pqr
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseScriptMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		want    *ScriptMetadata
+		wantErr string
+	}{
+		{
+			name: "none",
+			src:  "#pqr cat=Tools\nprint(1)\n",
+		},
+		{
+			name: "metadata",
+			src:  "# /// script\n# requires-python = \">=3.11\"\n# dependencies = [\n#   \"requests<3\",\n#   \"rich\",\n# ]\n# ///\n",
+			want: &ScriptMetadata{RequiresPython: ">=3.11", Dependencies: []string{"requests<3", "rich"}},
+		},
+		{
+			name: "CRLF and empty comment lines",
+			src:  "# /// script\r\n#\r\n# dependencies = [\"rich\"]\r\n# ///\r\nprint(1)\r\n",
+			want: &ScriptMetadata{Dependencies: []string{"rich"}},
+		},
+		{
+			name: "next to a #pqr block",
+			src:  "#pqr\n# cat=Tools\n#/pqr\n# /// script\n# requires-python = \">=3.12\"\n# ///\n",
+			want: &ScriptMetadata{RequiresPython: ">=3.12"},
+		},
+		{
+			name:    "unterminated",
+			src:     "# /// script\n# dependencies = []\nprint(1)\n",
+			wantErr: "line 1, col 1: unterminated # /// script block (missing # ///)",
+		},
+		{
+			name:    "bad TOML",
+			src:     "# /// script\n# dependencies = [\n# ///\n",
+			wantErr: "line 2, col 19: script metadata:",
+		},
+		{
+			name:    "duplicate",
+			src:     "# /// script\n# ///\n\n# /// script\n# ///\n",
+			wantErr: "line 4, col 1: duplicate # /// script block",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := Parse(strings.NewReader(tt.src))
+			if tt.wantErr != "" {
+				var errs ErrorList
+				if !errors.As(err, &errs) || !strings.Contains(errs[0].Error(), tt.wantErr) {
+					t.Fatalf("Parse error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !reflect.DeepEqual(h.Script, tt.want) {
+				t.Errorf("Script = %+v, want %+v", h.Script, tt.want)
+			}
+		})
+	}
+}
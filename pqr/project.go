@@ -0,0 +1,71 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package pqr
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProjectMarkers are the files and directories whose presence marks a
+// project root, checked in this order in each directory.
+var ProjectMarkers = []string{"pyproject.toml", "setup.cfg", "setup.py", "requirements.txt", ".git"}
+
+// FindProjectRoot walks up from dir and returns the first directory that
+// contains one of ProjectMarkers, or "" when none does.
+func FindProjectRoot(dir string) string {
+	for {
+		for _, m := range ProjectMarkers {
+			if _, err := os.Stat(filepath.Join(dir, m)); err == nil {
+				return dir
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// WorkDir returns the directory the script at scriptPath should run in:
+//
+//	cwd=script   the script's directory (the default)
+//	cwd=project  the nearest project root, or the script's directory
+//	cwd=<path>   that directory; relative paths start at the script
+func (h Header) WorkDir(scriptPath string) (string, error) {
+	scriptDir := filepath.Dir(scriptPath)
+	switch strings.ToLower(h.Cwd) {
+	case "", "script":
+		return scriptDir, nil
+	case "project":
+		if root := FindProjectRoot(scriptDir); root != "" {
+			return root, nil
+		}
+		return scriptDir, nil
+	}
+	dir := resolvePath(h.Cwd, scriptDir)
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("cwd: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("cwd: %s is not a directory", dir)
+	}
+	return dir, nil
+}
+
+// resolvePath expands a leading "~/" and makes path absolute against base.
+func resolvePath(path, base string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(base, path)
+	}
+	return path
+}
@@ -0,0 +1,309 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package pqr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Header line syntax:
+//
+//	line    = "#pqr" [ space entries ]
+//	entries = [ entry ] { ";" [ entry ] }
+//	entry   = key "=" value | key op bare
+//	key     = letter { letter | digit | "_" | "-" | "." }
+//	value   = bare | '"' { char | escape } '"' | "'" { char } "'"
+//	op      = "<" | ">" | "!" | "~"
+//
+// Whitespace around keys, "=" and values is ignored. Keys are
+// case-insensitive. A bare value runs up to the next ";" and may contain
+// "="; backslashes in it are literal, so Windows paths need no quoting.
+// Double-quoted values understand only the escapes \\ and \"; any other
+// backslash is kept as is, so `"C:\tools\new;dir"` is the path it looks
+// like. Single-quoted values are raw. The op form is shorthand for version
+// constraints: `py>=3.11` is read as key "py" with the value ">=3.11".
+//
+// The legacy forms `#pqr key "value"` and `#pqr terminal true` are still
+// accepted.
+//
+// Longer headers can be written as a block. A "#pqr" line with nothing after
+// it opens the block, every following "# entries" comment line belongs to it,
+// and "#/pqr" closes it. A comment line in the block that does not start
+// with a key and "=" (or a version operator) is a plain comment and skipped:
+//
+//	#pqr
+//	# Nightly report for the team
+//	# cat=Tools
+//	# win="C:\Python312\python.exe"
+//	# term=true
+//	#/pqr
+//
+// A block that runs into a non-comment line is reported as unterminated but
+// its entries are kept.
+
+// Block delimiters.
+const (
+	BlockStart = "#pqr"
+	BlockEnd   = "#/pqr"
+)
+
+type lineKind int
+
+const (
+	lineOther        lineKind = iota
+	lineSingle                // #pqr key=val; ...
+	lineBlockStart            // #pqr
+	lineBlockBody             // # key=val; ... inside a block
+	lineBlockComment          // any other comment inside a block
+	lineBlockEnd              // #/pqr
+)
+
+// classify tells what role line plays in a header. inBlock reports whether a
+// block is currently open.
+func classify(line string, inBlock bool) lineKind {
+	t := strings.TrimSpace(line)
+	if inBlock {
+		switch {
+		case strings.EqualFold(t, BlockEnd):
+			return lineBlockEnd
+		case isPqrLine(t):
+			// a new header line ends the open block
+		case strings.HasPrefix(t, "#"):
+			if !isEntryStart(t[1:]) {
+				return lineBlockComment
+			}
+			return lineBlockBody
+		}
+	}
+	if !isPqrLine(t) {
+		return lineOther
+	}
+	if len(t) == 4 {
+		return lineBlockStart
+	}
+	return lineSingle
+}
+
+// isEntryStart reports whether s, the text after "#" of a block line, starts
+// with an entry: a key followed by "=" or a version operator. Anything else,
+// such as "# Tool for the team", is a plain comment.
+func isEntryStart(s string) bool {
+	s = strings.TrimLeft(s, " \t")
+	if s == "" || !isKeyStart(s[0]) {
+		return false
+	}
+	i := 1
+	for i < len(s) && isKeyChar(s[i]) {
+		i++
+	}
+	s = strings.TrimLeft(s[i:], " \t")
+	return s != "" && strings.IndexByte("=<>!~", s[0]) >= 0
+}
+
+// isPqrLine reports whether s starts with "#pqr" as a whole word.
+func isPqrLine(s string) bool {
+	if len(s) < 4 || !strings.EqualFold(s[:4], "#pqr") {
+		return false
+	}
+	return len(s) == 4 || isSpace(s[4]) || s[4] == '\r'
+}
+
+// SyntaxError describes a problem in a #pqr line. Line and Column are 1-based;
+// Column counts bytes.
+type SyntaxError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d, col %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// ErrorList is the error returned by Parse when one or more #pqr lines are
+// malformed. The well-formed parts of the header are still applied.
+type ErrorList []*SyntaxError
+
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+}
+
+// Err returns l as an error, or nil if l is empty.
+func (l ErrorList) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	sort.SliceStable(l, func(i, j int) bool {
+		if l[i].Line != l[j].Line {
+			return l[i].Line < l[j].Line
+		}
+		return l[i].Column < l[j].Column
+	})
+	return l
+}
+
+// Entry is a single key=value pair. Key is lower-case.
+type Entry struct {
+	Key   string
+	Value string
+}
+
+func isKeyStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isKeyChar(c byte) bool {
+	return isKeyStart(c) || c >= '0' && c <= '9' || c == '_' || c == '-' || c == '.'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+// scannedEntry is an Entry together with where it sits in its line, as
+// 1-based columns: the key starts at col and the value spans [valCol, endCol).
+type scannedEntry struct {
+	Entry
+	col, valCol, endCol int
+	op                  bool // written as `key<op>...` without "="
+}
+
+// scanEntries parses the text after "#pqr". col is the 1-based column of
+// s[0] in the source line; the returned errors have Line unset.
+func scanEntries(s string, col int) ([]scannedEntry, ErrorList) {
+	var entries []scannedEntry
+	var errs ErrorList
+	fail := func(at int, format string, args ...any) {
+		errs = append(errs, &SyntaxError{Column: col + at, Msg: fmt.Sprintf(format, args...)})
+	}
+	skipSpace := func(i int) int {
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+		return i
+	}
+	skipEntry := func(i int) int {
+		for i < len(s) && s[i] != ';' {
+			i++
+		}
+		return i
+	}
+
+	i := 0
+	for {
+		i = skipSpace(i)
+		if i >= len(s) {
+			break
+		}
+		if s[i] == ';' {
+			i++
+			continue
+		}
+
+		keyStart := i
+		if !isKeyStart(s[i]) {
+			fail(i, "expected key, found %q", s[i])
+			i = skipEntry(i)
+			continue
+		}
+		for i < len(s) && isKeyChar(s[i]) {
+			i++
+		}
+		key := strings.ToLower(s[keyStart:i])
+
+		i = skipSpace(i)
+		if i < len(s) && strings.IndexByte("<>!~", s[i]) >= 0 {
+			valStart := i
+			i = skipEntry(i)
+			value := strings.TrimSpace(s[valStart:i])
+			entries = append(entries, scannedEntry{Entry{Key: key, Value: value}, col + keyStart, col + valStart, col + valStart + len(value), true})
+			continue
+		}
+		if i >= len(s) || s[i] != '=' {
+			fail(i, "expected '=' after key %q", key)
+			i = skipEntry(i)
+			continue
+		}
+		i = skipSpace(i + 1)
+
+		var value string
+		valStart, valEnd := i, i
+		if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+			v, n, ok := unquote(s[i:])
+			if !ok {
+				fail(i, "unterminated quoted value for %q", key)
+				break
+			}
+			value = v
+			valEnd = i + n
+			i = skipSpace(i + n)
+			if i < len(s) && s[i] != ';' {
+				fail(i, "unexpected %q after quoted value of %q", s[i], key)
+				i = skipEntry(i)
+			}
+		} else {
+			i = skipEntry(i)
+			value = strings.TrimSpace(s[valStart:i])
+			valEnd = valStart + len(value)
+		}
+		entries = append(entries, scannedEntry{Entry{Key: key, Value: value}, col + keyStart, col + valStart, col + valEnd, false})
+	}
+	return entries, errs
+}
+
+// unquote decodes the quoted string at the start of s and returns it together
+// with the number of bytes consumed.
+func unquote(s string) (string, int, bool) {
+	q := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == q:
+			return b.String(), i + 1, true
+		case c == '\\' && q == '"' && i+1 < len(s) && (s[i+1] == '\\' || s[i+1] == '"'):
+			b.WriteByte(s[i+1])
+			i++
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return "", len(s), false
+}
+
+// formatEntry renders key and value as an entry, using the op form for
+// values such as ">=3.11".
+func formatEntry(key, value string) string {
+	v := Quote(value)
+	if v != "" && strings.IndexByte("<>!~", v[0]) >= 0 {
+		return key + v
+	}
+	return key + "=" + v
+}
+
+// Quote returns v in a form that scanEntries reads back unchanged: bare when
+// possible, single-quoted when v has no single quote, and double-quoted with
+// \\ and \" escapes otherwise. A value lives on one line, so line breaks in
+// v are written as spaces.
+func Quote(v string) string {
+	v = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(v)
+	if v == "" {
+		return ""
+	}
+	if !strings.Contains(v, ";") && v == strings.TrimSpace(v) && v[0] != '"' && v[0] != '\'' {
+		return v
+	}
+	if !strings.Contains(v, "'") {
+		return "'" + v + "'"
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + r.Replace(v) + `"`
+}
@@ -0,0 +1,158 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package pqr
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want Header
+	}{
+		{
+			name: "bare windows path",
+			src:  `#pqr win=C:\tools\new\python.exe`,
+			want: Header{Found: true, Win: `C:\tools\new\python.exe`},
+		},
+		{
+			name: "double-quoted windows path with semicolon",
+			src:  `#pqr win="C:\tools\new;dir\python.exe"; cat=Tools`,
+			want: Header{Found: true, Win: `C:\tools\new;dir\python.exe`, Category: "Tools"},
+		},
+		{
+			name: "double-quoted escapes",
+			src:  `#pqr args="say \"hi\" \\ bye"`,
+			want: Header{Found: true, Args: `say "hi" \ bye`},
+		},
+		{
+			name: "single-quoted is raw",
+			src:  `#pqr cat='a;b \"c'`,
+			want: Header{Found: true, Category: `a;b \"c`},
+		},
+		{
+			name: "bare value keeps equals",
+			src:  `#pqr env=DEBUG=1; cat=Tools`,
+			want: Header{Found: true, Env: []string{"DEBUG=1"}, Category: "Tools"},
+		},
+		{
+			name: "op form",
+			src:  `#pqr py>=3.11,<3.13`,
+			want: Header{Found: true, Py: ">=3.11,<3.13"},
+		},
+		{
+			name: "legacy lines",
+			src:  "#pqr cat \"Old Tools\"\n#pqr terminal true",
+			want: Header{Found: true, Category: "Old Tools", Term: ptr(true)},
+		},
+		{
+			name: "block with BOM and CRLF",
+			src:  BOM + "#pqr\r\n# cat=Tools; linux=/usr/bin/python3\r\n#/pqr\r\nprint(1)\r\n",
+			want: Header{Found: true, Category: "Tools", Linux: "/usr/bin/python3"},
+		},
+		{
+			name: "indented block",
+			src:  "def f():\n    #pqr\n    # cat=Tools\n    #/pqr\n",
+			want: Header{Found: true, Category: "Tools"},
+		},
+		{
+			name: "block and single line merge",
+			src:  "#pqr\n# cat=A\n# term=false\n#/pqr\n#pqr cat=B\n",
+			want: Header{Found: true, Category: "B", Term: ptr(false)},
+		},
+		{
+			name: "comment after block is not an entry",
+			src:  "#pqr\n# cat=A\n#/pqr\n# cat=B\n",
+			want: Header{Found: true, Category: "A"},
+		},
+		{
+			name: "plain comments in a block",
+			src:  "#pqr\n# Nightly report for the team\n#\n# Note: needs VPN; see wiki\n# cat=Tools\n# py >=3.11\n#/pqr\n",
+			want: Header{Found: true, Category: "Tools", Py: ">=3.11"},
+		},
+		{
+			name: "later line wins",
+			src:  "#pqr cat=A\n#pqr cat=B",
+			want: Header{Found: true, Category: "B"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tt.src))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string // SyntaxError.Error() of each problem
+	}{
+		{"missing equals", "#pqr cat Tools", []string{`line 1, col 10: expected '=' after key "cat"`}},
+		{"unterminated block", "#pqr\n# cat=A\nprint(1)", []string{"line 1, col 1: unterminated #pqr block (missing #/pqr)"}},
+		{"block ended by #pqr line", "#pqr\n# cat=A\n#pqr term=true", []string{"line 1, col 1: unterminated #pqr block (missing #/pqr)"}},
+		{"bad entry after a key in a block", "#pqr\n# cat=A; this is text\n#/pqr", []string{`line 2, col 15: expected '=' after key "this"`}},
+		{"unterminated quote", `#pqr win="C:\x`, []string{`line 1, col 10: unterminated quoted value for "win"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.src))
+			var errs ErrorList
+			if !errors.As(err, &errs) {
+				t.Fatalf("Parse error = %v, want an ErrorList", err)
+			}
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Error())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("errors = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteRoundTrip(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Tools", "Tools"},
+		{`C:\Python312\python.exe`, `C:\Python312\python.exe`},
+		{`C:\tools\new;dir\python.exe`, `'C:\tools\new;dir\python.exe'`},
+		{" padded ", "' padded '"},
+		{`"quoted"`, `'"quoted"'`},
+		{`it's; C:\x "y"`, `"it's; C:\\x \"y\""`},
+		{"a=b", "a=b"},
+	}
+	for _, tt := range tests {
+		got := Quote(tt.value)
+		if got != tt.want {
+			t.Errorf("Quote(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+		entries, errs := scanEntries("cat="+got, 1)
+		if len(errs) > 0 || len(entries) != 1 || entries[0].Value != tt.value {
+			t.Errorf("scanEntries(%q) = %+v, %v; want value %q", "cat="+got, entries, errs, tt.value)
+		}
+	}
+}
+
+func TestQuoteLineBreaks(t *testing.T) {
+	if got, want := Quote("a\r\nb\nc"), "a b c"; got != want {
+		t.Errorf("Quote = %q, want %q", got, want)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,99 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"pqr"
+	"pqr/launch"
+)
+
+// WatchInterpreter probes the interpreter in entry whenever it changes and
+// shows the version, implementation and venv state in the returned label, or
+// the error in red. onValid is called on the UI thread with each value that
+// works; an empty value stands for launch.DefaultPython(). It takes over
+// entry.OnChanged.
+func WatchInterpreter(entry *widget.Entry, onValid func(string)) *widget.Label {
+	status := widget.NewLabel("")
+	status.Wrapping = fyne.TextWrapWord
+	check := func(value string) {
+		python := strings.TrimSpace(value)
+		if python == "" {
+			python = launch.DefaultPython()
+		}
+		info, err := launch.Probe(python)
+		fyne.Do(func() {
+			if entry.Text != value {
+				return // edited again while probing
+			}
+			if err != nil {
+				status.Importance = widget.DangerImportance
+				status.SetText(err.Error())
+				return
+			}
+			status.Importance = widget.MediumImportance
+			status.SetText(info.String())
+			onValid(value)
+		})
+	}
+
+	var timer *time.Timer
+	entry.OnChanged = func(s string) {
+		if timer != nil {
+			timer.Stop()
+		}
+		status.Importance = widget.LowImportance
+		status.SetText("Checking...")
+		timer = time.AfterFunc(300*time.Millisecond, func() { check(s) })
+	}
+	go check(entry.Text)
+	return status
+}
+
+// CheckNamedInterpreter checks a named interpreter before it is saved: name
+// must pass pqr.IsInterpreterName and python must pass launch.Probe, which
+// runs off the UI thread. add is called on the UI thread when both do;
+// otherwise the problem is shown in a dialog on w.
+func CheckNamedInterpreter(w fyne.Window, name, python string, add func()) {
+	if !pqr.IsInterpreterName(name) || python == "" {
+		dialog.ShowInformation("Named Interpreter", "Enter a name that starts with a letter and has no spaces, quotes or ';=<>!~', and an interpreter path.", w)
+		return
+	}
+	go func() {
+		_, err := launch.Probe(python)
+		fyne.Do(func() {
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("%s: %v", name, err), w)
+				return
+			}
+			add()
+		})
+	}()
+}
+
+// OfferInterpreterMapping asks whether to map the unknown py= name to an
+// interpreter on this machine and, once one is chosen and passes
+// CheckNamedInterpreter, calls mapped with its path.
+func OfferInterpreterMapping(w fyne.Window, name string, mapped func(python string)) {
+	msg := fmt.Sprintf("This script uses py=%s, but no interpreter named %q is registered on this machine.\n\nChoose an interpreter for %q now?", name, name, name)
+	dialog.ShowConfirm("Unknown Interpreter", msg, func(ok bool) {
+		if !ok {
+			return
+		}
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			reader.Close()
+			python := reader.URI().Path()
+			CheckNamedInterpreter(w, name, python, func() { mapped(python) })
+		}, w)
+	}, w)
+}
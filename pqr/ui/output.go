@@ -0,0 +1,122 @@
+// Created by DINKIssTyle on 2026. Copyright (C) 2026 DINKI'ssTyle. All rights reserved.
+
+// Package ui holds the fyne widgets and dialogs that PyQuickRun and
+// PyQuickBox share: the run output view and the interpreter checks.
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"pqr/ansi"
+	"pqr/launch"
+)
+
+// Output shows the output of a run in a TextGrid as it is written: stderr in
+// the error color, ANSI colors as the script sets them, and \r and \b moving
+// the cursor the way a terminal would. Its methods must run on the UI
+// thread.
+type Output struct {
+	Grid    *widget.TextGrid
+	MaxRows int // lines kept; older lines are dropped
+
+	parsers [2]ansi.Parser // per launch.Stream
+	col     int            // cursor column in the last row, for \r
+}
+
+// NewOutput returns an empty Output that keeps maxRows lines.
+func NewOutput(maxRows int) *Output {
+	o := &Output{Grid: widget.NewTextGrid(), MaxRows: maxRows}
+	o.Clear()
+	return o
+}
+
+// Clear removes all output.
+func (o *Output) Clear() {
+	o.Grid.Rows = []widget.TextGridRow{{}}
+	o.parsers = [2]ansi.Parser{}
+	o.col = 0
+	o.Grid.Refresh()
+}
+
+// Write shows a chunk of stream.
+func (o *Output) Write(stream launch.Stream, data string) {
+	for _, span := range o.parsers[stream].Parse(data) {
+		style := cellStyle(span.Style, stream)
+		for _, r := range span.Text {
+			last := len(o.Grid.Rows) - 1
+			switch r {
+			case '\n':
+				o.Grid.Rows = append(o.Grid.Rows, widget.TextGridRow{})
+				o.col = 0
+			case '\r':
+				o.col = 0
+			case '\b':
+				if o.col > 0 {
+					o.col--
+				}
+			default:
+				cell := widget.TextGridCell{Rune: r, Style: style}
+				if cells := o.Grid.Rows[last].Cells; o.col < len(cells) {
+					cells[o.col] = cell
+				} else {
+					o.Grid.Rows[last].Cells = append(cells, cell)
+				}
+				o.col++
+			}
+		}
+	}
+	o.show()
+}
+
+// Note adds msg on a line of its own, muted and set apart from the output,
+// such as how the run ended.
+func (o *Output) Note(msg string) {
+	if len(o.Grid.Rows[len(o.Grid.Rows)-1].Cells) > 0 {
+		o.Grid.Rows = append(o.Grid.Rows, widget.TextGridRow{})
+	}
+	o.col = 0
+	muted := &widget.CustomTextGridStyle{FGColor: theme.Color(theme.ColorNamePlaceHolder), TextStyle: fyne.TextStyle{Italic: true}}
+	var row widget.TextGridRow
+	for _, r := range "--- " + msg + " ---" {
+		row.Cells = append(row.Cells, widget.TextGridCell{Rune: r, Style: muted})
+	}
+	o.Grid.Rows = append(o.Grid.Rows, widget.TextGridRow{}, row)
+	o.show()
+}
+
+// show drops the lines beyond MaxRows and scrolls to the end.
+func (o *Output) show() {
+	if extra := len(o.Grid.Rows) - o.MaxRows; o.MaxRows > 0 && extra > 0 {
+		o.Grid.Rows = o.Grid.Rows[extra:]
+	}
+	o.Grid.Refresh()
+	o.Grid.ScrollToBottom()
+}
+
+// cellStyle turns an ANSI style into a TextGrid style. Text without a color
+// of its own is shown in the error color on stderr.
+func cellStyle(s ansi.Style, stream launch.Stream) widget.TextGridStyle {
+	fg, bg := s.FG, s.BG
+	if fg == nil && stream == launch.Stderr {
+		fg = theme.Color(theme.ColorNameError)
+	}
+	if s.Inverse {
+		if fg == nil {
+			fg = theme.Color(theme.ColorNameForeground)
+		}
+		if bg == nil {
+			bg = theme.Color(theme.ColorNameBackground)
+		}
+		fg, bg = bg, fg
+	}
+	if fg == nil && bg == nil && !s.Bold && !s.Italic {
+		return nil
+	}
+	return &widget.CustomTextGridStyle{
+		FGColor:   fg,
+		BGColor:   bg,
+		TextStyle: fyne.TextStyle{Bold: s.Bold, Italic: s.Italic},
+	}
+}